@@ -143,9 +143,10 @@ func runMemory(c *cli.Context) {
 		Fatalln("Memory limit must be set")
 	}
 	memoryDriver := server.MemoryFactory{
-		Config:     apiServerConfig,
-		MaxMemory:  maxMemory,
-		Expiration: expiration,
+		Config:         apiServerConfig,
+		MaxMemory:      maxMemory,
+		Expiration:     expiration,
+		WebsiteAddress: c.GlobalString("address-website"),
 	}
 	apiServer := memoryDriver.GetStartServerFunc()
 	//	webServer := getWebServerConfigFunc(c)
@@ -173,8 +174,9 @@ func runDonut(c *cli.Context) {
 	}
 	apiServerConfig := getAPIServerConfig(c)
 	donutDriver := server.DonutFactory{
-		Config: apiServerConfig,
-		Paths:  paths,
+		Config:         apiServerConfig,
+		Paths:          paths,
+		WebsiteAddress: c.GlobalString("address-website"),
 	}
 	apiServer := donutDriver.GetStartServerFunc()
 	//	webServer := getWebServerConfigFunc(c)
@@ -188,8 +190,9 @@ func runFilesystem(c *cli.Context) {
 	}
 	apiServerConfig := getAPIServerConfig(c)
 	fsDriver := server.FilesystemFactory{
-		Config: apiServerConfig,
-		Path:   c.Args()[0],
+		Config:         apiServerConfig,
+		Path:           c.Args()[0],
+		WebsiteAddress: c.GlobalString("address-website"),
 	}
 	apiServer := fsDriver.GetStartServerFunc()
 	//	webServer := getWebServerConfigFunc(c)
@@ -44,6 +44,14 @@ var flags = []cli.Flag{
 		Value: ":9001",
 		Usage: "ADDRESS:PORT for management console access",
 	},
+	cli.StringFlag{
+		Name:  "address-website",
+		Usage: "ADDRESS:PORT for static website access to buckets, disabled when empty",
+	},
+	cli.StringFlag{
+		Name:  "domain",
+		Usage: "DOMAIN for virtual hosted style access to buckets as BUCKET.DOMAIN, path style only when empty",
+	},
 	cli.IntFlag{
 		Name:  "ratelimit",
 		Value: 16,
@@ -84,6 +92,7 @@ func getAPIServerConfig(c *cli.Context) httpserver.Config {
 		CertFile:  certFile,
 		KeyFile:   keyFile,
 		RateLimit: c.GlobalInt("ratelimit"),
+		Domain:    c.GlobalString("domain"),
 	}
 }
 
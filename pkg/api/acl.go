@@ -16,14 +16,29 @@
 
 package api
 
-import "net/http"
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/minio/minio/pkg/iodine"
+	"github.com/minio/minio/pkg/storage/drivers"
+	"github.com/minio/minio/pkg/utils/log"
+)
 
 // Please read for more information - http://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html#canned-acl
 //
-// Here We are only supporting 'acl's through request headers not through their request body
+// Canned ACLs are set through the 'x-amz-acl' request header, object ACLs may as well be set through
+// an AccessControlPolicy request body listing their grants
 // http://docs.aws.amazon.com/AmazonS3/latest/dev/acl-overview.html#setting-acls
+//
+// Every configured user acts as the owner of buckets and objects, so grants only ever widen what
+// requests without credentials may do.
 
-// Minio only supports three types for now i.e 'private, public-read, public-read-write'
+// maximum size of an access control policy document
+const maxACLSize = 64 * 1024
 
 // ACLType - different acl types
 type ACLType int
@@ -33,25 +48,58 @@ const (
 	privateACLType
 	publicReadACLType
 	publicReadWriteACLType
+	authenticatedReadACLType
+	bucketOwnerReadACLType
+	bucketOwnerFullControlACLType
+)
+
+// permissions of ACL grants
+const (
+	permissionFullControl = "FULL_CONTROL"
+	permissionRead        = "READ"
+	permissionWrite       = "WRITE"
+	permissionReadACP     = "READ_ACP"
+	permissionWriteACP    = "WRITE_ACP"
 )
 
+// groups ACL grants may be given to
+const (
+	allUsersGroup           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	authenticatedUsersGroup = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+// canonical id and name of the owner of buckets and objects
+const ownerID = "minio"
+
+// xml namespace of the grantee type attribute
+const xmlSchemaInstance = "http://www.w3.org/2001/XMLSchema-instance"
+
+var errMalformedACL = errors.New("Malformed access control policy")
+var errUnsupportedGrantee = errors.New("Unsupported grantee")
+
 // Get acl type requested from 'x-amz-acl' header
 func getACLType(req *http.Request) ACLType {
-	aclHeader := req.Header.Get("x-amz-acl")
-	if aclHeader != "" {
-		switch {
-		case aclHeader == "private":
-			return privateACLType
-		case aclHeader == "public-read":
-			return publicReadACLType
-		case aclHeader == "public-read-write":
-			return publicReadWriteACLType
-		default:
-			return unsupportedACLType
-		}
+	return parseACLType(req.Header.Get("x-amz-acl"))
+}
+
+// parseACLType - acl type of a canned acl name, an empty name is private
+func parseACLType(acl string) ACLType {
+	switch acl {
+	case "", "private":
+		return privateACLType
+	case "public-read":
+		return publicReadACLType
+	case "public-read-write":
+		return publicReadWriteACLType
+	case "authenticated-read":
+		return authenticatedReadACLType
+	case "bucket-owner-read":
+		return bucketOwnerReadACLType
+	case "bucket-owner-full-control":
+		return bucketOwnerFullControlACLType
+	default:
+		return unsupportedACLType
 	}
-	// make it default private
-	return privateACLType
 }
 
 // ACL type to human readable string
@@ -69,6 +117,18 @@ func getACLTypeString(acl ACLType) string {
 		{
 			return "public-read-write"
 		}
+	case authenticatedReadACLType:
+		{
+			return "authenticated-read"
+		}
+	case bucketOwnerReadACLType:
+		{
+			return "bucket-owner-read"
+		}
+	case bucketOwnerFullControlACLType:
+		{
+			return "bucket-owner-full-control"
+		}
 	case unsupportedACLType:
 		{
 			return ""
@@ -77,3 +137,248 @@ func getACLTypeString(acl ACLType) string {
 		return "private"
 	}
 }
+
+// getCannedACLGrants - grants of a canned acl, the owner always has full control. Buckets and
+// objects have the same owner, so the bucket-owner-* acls are private.
+func getCannedACLGrants(acl ACLType) []drivers.Grant {
+	grants := []drivers.Grant{{GranteeID: ownerID, Permission: permissionFullControl}}
+	switch acl {
+	case publicReadACLType:
+		grants = append(grants, drivers.Grant{GranteeURI: allUsersGroup, Permission: permissionRead})
+	case publicReadWriteACLType:
+		grants = append(grants,
+			drivers.Grant{GranteeURI: allUsersGroup, Permission: permissionRead},
+			drivers.Grant{GranteeURI: allUsersGroup, Permission: permissionWrite})
+	case authenticatedReadACLType:
+		grants = append(grants, drivers.Grant{GranteeURI: authenticatedUsersGroup, Permission: permissionRead})
+	}
+	return grants
+}
+
+// getObjectGrants - grants of an object acl, objects without acl are private
+func getObjectGrants(grants []drivers.Grant) []drivers.Grant {
+	if len(grants) == 0 {
+		return getCannedACLGrants(privateACLType)
+	}
+	return grants
+}
+
+// getStoredObjectGrants - grants of an object acl as drivers keep them, nil for the private default
+func getStoredObjectGrants(grants []drivers.Grant) []drivers.Grant {
+	if isSameGrants(grants, getCannedACLGrants(privateACLType)) {
+		return nil
+	}
+	return grants
+}
+
+// getBucketACLType - canned acl of a bucket with the given grants, the bucket acl of drivers is
+// always one of the canned acls
+func getBucketACLType(grants []drivers.Grant) ACLType {
+	for _, acl := range []ACLType{privateACLType, publicReadACLType, publicReadWriteACLType, authenticatedReadACLType} {
+		if isSameGrants(grants, getCannedACLGrants(acl)) {
+			return acl
+		}
+	}
+	return unsupportedACLType
+}
+
+// isSameGrants - both lists hold the same grants in any order
+func isSameGrants(grants, otherGrants []drivers.Grant) bool {
+	set := make(map[drivers.Grant]bool)
+	for _, grant := range grants {
+		set[grant] = true
+	}
+	otherSet := make(map[drivers.Grant]bool)
+	for _, grant := range otherGrants {
+		if !set[grant] {
+			return false
+		}
+		otherSet[grant] = true
+	}
+	return len(set) == len(otherSet)
+}
+
+// hasGrant - the grants give the permission to a grantee group, full control includes every permission
+func hasGrant(grants []drivers.Grant, group, permission string) bool {
+	for _, grant := range grants {
+		if grant.GranteeURI != group {
+			continue
+		}
+		if grant.Permission == permission || grant.Permission == permissionFullControl {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAccessControlPolicy - decode and validate the grants of an access control policy
+//
+//	<AccessControlPolicy>
+//	  <Owner><ID>minio</ID></Owner>
+//	  <AccessControlList>
+//	    <Grant>
+//	      <Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group">
+//	        <URI>http://acs.amazonaws.com/groups/global/AllUsers</URI>
+//	      </Grantee>
+//	      <Permission>READ</Permission>
+//	    </Grant>
+//	  </AccessControlList>
+//	</AccessControlPolicy>
+//
+// The type of a grantee follows from the element naming it, grants by email address are not supported.
+func parseAccessControlPolicy(data []byte) ([]drivers.Grant, error) {
+	policy := new(AccessControlPolicy)
+	if err := xml.Unmarshal(data, policy); err != nil {
+		return nil, errMalformedACL
+	}
+	var grants []drivers.Grant
+	for _, grant := range policy.AccessControlList.Grant {
+		switch grant.Permission {
+		case permissionFullControl, permissionRead, permissionWrite, permissionReadACP, permissionWriteACP:
+		default:
+			return nil, errMalformedACL
+		}
+		grantee := grant.Grantee
+		switch {
+		case grantee.EmailAddress != "":
+			return nil, errUnsupportedGrantee
+		case grantee.ID != "" && grantee.URI == "":
+			grants = append(grants, drivers.Grant{GranteeID: grantee.ID, Permission: grant.Permission})
+		case grantee.URI != "" && grantee.ID == "":
+			if grantee.URI != allUsersGroup && grantee.URI != authenticatedUsersGroup {
+				return nil, errMalformedACL
+			}
+			grants = append(grants, drivers.Grant{GranteeURI: grantee.URI, Permission: grant.Permission})
+		default:
+			return nil, errMalformedACL
+		}
+	}
+	return grants, nil
+}
+
+// generateAccessControlPolicy - access control policy document of a list of grants
+func generateAccessControlPolicy(grants []drivers.Grant) AccessControlPolicy {
+	policy := AccessControlPolicy{}
+	policy.Owner.ID = ownerID
+	policy.Owner.DisplayName = ownerID
+	for _, grant := range grants {
+		grantee := Grantee{XMLNS: xmlSchemaInstance}
+		switch {
+		case grant.GranteeURI != "":
+			grantee.Type = "Group"
+			grantee.URI = grant.GranteeURI
+		default:
+			grantee.Type = "CanonicalUser"
+			grantee.ID = grant.GranteeID
+			if grant.GranteeID == ownerID {
+				grantee.DisplayName = ownerID
+			}
+		}
+		policy.AccessControlList.Grant = append(policy.AccessControlList.Grant, Grant{
+			Grantee:    grantee,
+			Permission: grant.Permission,
+		})
+	}
+	return policy
+}
+
+// getACLPermission - permission an acl has to grant for a bucket policy action, owner only actions need none
+func getACLPermission(action string) string {
+	switch action {
+	case "s3:ListBucket", "s3:ListBucketVersions", "s3:ListBucketMultipartUploads",
+		"s3:GetObject", "s3:GetObjectVersion", "s3:ListMultipartUploadParts":
+		return permissionRead
+	case "s3:PutObject", "s3:DeleteObject", "s3:DeleteObjectVersion", "s3:AbortMultipartUpload":
+		return permissionWrite
+	case "s3:GetBucketAcl", "s3:GetObjectAcl", "s3:GetObjectVersionAcl":
+		return permissionReadACP
+	case "s3:PutBucketAcl", "s3:PutObjectAcl":
+		return permissionWriteACP
+	}
+	return ""
+}
+
+// isAnonymousAllowed - check the acls for a request without credentials. The bucket acl gives access to
+// the bucket and all of its objects, an object acl to a single object. Writing objects is only ever
+// granted by the bucket acl.
+func (server *minioAPI) isAnonymousAllowed(bucketACL drivers.BucketACL, bucket, object, permission string) (bool, error) {
+	switch permission {
+	case permissionRead:
+		if bucketACL.IsPublicRead() || bucketACL.IsPublicReadWrite() {
+			return true, nil
+		}
+	case permissionWrite:
+		return bucketACL.IsPublicReadWrite(), nil
+	case "":
+		return false, nil
+	}
+	if object == "" {
+		return false, nil
+	}
+	metadata, err := server.driver.GetObjectMetadata(bucket, object)
+	switch iodine.ToError(err).(type) {
+	case nil:
+	case drivers.ObjectNotFound, drivers.ObjectNameInvalid:
+		return false, nil
+	default:
+		return false, iodine.New(err, nil)
+	}
+	return hasGrant(getObjectGrants(metadata.ACL), allUsersGroup, permission), nil
+}
+
+// isReadableByEveryone - an object may be read without credentials, through the bucket policy or the acls
+func (server *minioAPI) isReadableByEveryone(req *http.Request, bucket, object string) (bool, error) {
+	decision, err := server.getBucketPolicyDecision(req, bucket, object, "s3:GetObject", "")
+	if err != nil {
+		return false, iodine.New(err, nil)
+	}
+	switch decision {
+	case policyAllow:
+		return true, nil
+	case policyDeny:
+		return false, nil
+	}
+	bucketMetadata, err := server.driver.GetBucketMetadata(bucket)
+	if err != nil {
+		return false, iodine.New(err, nil)
+	}
+	return server.isAnonymousAllowed(bucketMetadata.ACL, bucket, object, permissionRead)
+}
+
+// getRequestACLGrants - grants of a PUT ?acl request, either of the canned acl in the x-amz-acl header
+// or of the access control policy in the request body. Writes the error response when the acl is invalid.
+func getRequestACLGrants(w http.ResponseWriter, req *http.Request, acceptsContentType contentType) ([]drivers.Grant, bool) {
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxACLSize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return nil, false
+	}
+	if len(body) > maxACLSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return nil, false
+	}
+	if req.Header.Get("x-amz-acl") != "" || len(body) == 0 {
+		// an acl is either canned or listed in the body, never both
+		if len(body) > 0 {
+			writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+			return nil, false
+		}
+		aclType := getACLType(req)
+		if aclType == unsupportedACLType {
+			writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+			return nil, false
+		}
+		return getCannedACLGrants(aclType), true
+	}
+	grants, err := parseAccessControlPolicy(body)
+	switch err {
+	case nil:
+		return grants, true
+	case errUnsupportedGrantee:
+		writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+	default:
+		writeErrorResponse(w, req, MalformedACLError, acceptsContentType, req.URL.Path)
+	}
+	return nil, false
+}
@@ -17,7 +17,15 @@
 package api
 
 import (
+	"bytes"
+	cryptomd5 "crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/minio/minio/pkg/iodine"
@@ -42,24 +50,48 @@ func (server *minioAPI) isValidOp(w http.ResponseWriter, req *http.Request, acce
 			return false
 		}
 	case nil:
-		if _, err := stripAuth(req); err != nil {
-			if bucketMetadata.ACL.IsPrivate() {
-				return true
-				//uncomment this when we have webcli
-				//writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
-				//return false
+		object := vars["object"]
+		accessKey := getRequestAccessKey(req)
+		action := getPolicyAction(req, object)
+		// an explicit deny of the bucket policy applies to everyone but configured users managing the policy,
+		// an explicit allow needs no further checks
+		if action != "" && (accessKey == "" || !policyManagementActions[action]) {
+			decision, err := server.getBucketPolicyDecision(req, bucket, object, action, accessKey)
+			if err != nil {
+				log.Error.Println(iodine.New(err, nil))
+				writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+				return false
 			}
-			if bucketMetadata.ACL.IsPublicRead() && req.Method == "PUT" {
+			switch decision {
+			case policyDeny:
+				writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
+				return false
+			case policyAllow:
 				return true
-				//uncomment this when we have webcli
-				//writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
-				//return false
 			}
 		}
+		// signed and presigned requests were verified by validateAuthHeaderHandler, browser uploads
+		// verify the signature of their POST policy themselves
+		if accessKey != "" || isRequestPostPolicy(req, object) {
+			return true
+		}
+		// without credentials the bucket and object acls decide, owner only operations need no permission
+		// and are never allowed
+		allowed, err := server.isAnonymousAllowed(bucketMetadata.ACL, bucket, object, getACLPermission(action))
+		if err != nil {
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+			return false
+		}
+		if !allowed {
+			writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
+			return false
+		}
 	default:
 		{
 			log.Error.Println(iodine.New(err, nil))
 			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+			return false
 		}
 	}
 	return true
@@ -76,6 +108,10 @@ func (server *minioAPI) listMultipartUploadsHandler(w http.ResponseWriter, req *
 	acceptsContentType := getContentType(req)
 
 	resources := getBucketMultipartResources(req.URL.Query())
+	if !isValidEncodingType(resources.EncodingType) {
+		writeErrorResponse(w, req, InvalidArgument, acceptsContentType, req.URL.Path)
+		return
+	}
 	if resources.MaxUploads == 0 {
 		resources.MaxUploads = maxObjectList
 	}
@@ -126,7 +162,62 @@ func (server *minioAPI) listObjectsHandler(w http.ResponseWriter, req *http.Requ
 		return
 	}
 
+	if isRequestBucketPolicy(req.URL.Query()) {
+		server.getBucketPolicyHandler(w, req)
+		return
+	}
+
+	if isRequestACL(req.URL.Query()) {
+		server.getBucketACLHandler(w, req)
+		return
+	}
+
+	if isRequestBucketCORS(req.URL.Query()) {
+		server.getBucketCORSHandler(w, req)
+		return
+	}
+
+	if isRequestBucketLifecycle(req.URL.Query()) {
+		server.getBucketLifecycleHandler(w, req)
+		return
+	}
+
+	if isRequestBucketVersioning(req.URL.Query()) {
+		server.getBucketVersioningHandler(w, req)
+		return
+	}
+
+	if isRequestBucketVersions(req.URL.Query()) {
+		server.listObjectVersionsHandler(w, req)
+		return
+	}
+
+	if isRequestTagging(req.URL.Query()) {
+		server.getBucketTaggingHandler(w, req)
+		return
+	}
+	if isRequestBucketWebsite(req.URL.Query()) {
+		server.getBucketWebsiteHandler(w, req)
+		return
+	}
+	if isRequestBucketNotification(req.URL.Query()) {
+		server.getBucketNotificationHandler(w, req)
+		return
+	}
+	if isRequestBucketLogging(req.URL.Query()) {
+		server.getBucketLoggingHandler(w, req)
+		return
+	}
+	if isRequestListObjectsV2(req.URL.Query()) {
+		server.listObjectsV2Handler(w, req)
+		return
+	}
+
 	resources := getBucketResources(req.URL.Query())
+	if !isValidEncodingType(resources.EncodingType) {
+		writeErrorResponse(w, req, InvalidArgument, acceptsContentType, req.URL.Path)
+		return
+	}
 	if resources.Maxkeys == 0 {
 		resources.Maxkeys = maxObjectList
 	}
@@ -205,10 +296,42 @@ func (server *minioAPI) putBucketHandler(w http.ResponseWriter, req *http.Reques
 	// 	writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
 	//	return
 	// }
-	if isRequestBucketACL(req.URL.Query()) {
+	if isRequestACL(req.URL.Query()) {
 		server.putBucketACLHandler(w, req)
 		return
 	}
+	if isRequestBucketPolicy(req.URL.Query()) {
+		server.putBucketPolicyHandler(w, req)
+		return
+	}
+	if isRequestBucketCORS(req.URL.Query()) {
+		server.putBucketCORSHandler(w, req)
+		return
+	}
+	if isRequestBucketLifecycle(req.URL.Query()) {
+		server.putBucketLifecycleHandler(w, req)
+		return
+	}
+	if isRequestBucketVersioning(req.URL.Query()) {
+		server.putBucketVersioningHandler(w, req)
+		return
+	}
+	if isRequestTagging(req.URL.Query()) {
+		server.putBucketTaggingHandler(w, req)
+		return
+	}
+	if isRequestBucketWebsite(req.URL.Query()) {
+		server.putBucketWebsiteHandler(w, req)
+		return
+	}
+	if isRequestBucketNotification(req.URL.Query()) {
+		server.putBucketNotificationHandler(w, req)
+		return
+	}
+	if isRequestBucketLogging(req.URL.Query()) {
+		server.putBucketLoggingHandler(w, req)
+		return
+	}
 	// read from 'x-amz-acl'
 	aclType := getACLType(req)
 	if aclType == unsupportedACLType {
@@ -248,11 +371,25 @@ func (server *minioAPI) putBucketHandler(w http.ResponseWriter, req *http.Reques
 
 // PUT Bucket ACL
 // ----------
-// This implementation of the PUT operation modifies the bucketACL for authenticated request
+// This implementation of the PUT operation modifies the bucketACL for authenticated request, either
+// through the 'x-amz-acl' header or an access control policy body. Bucket ACLs are always one of the
+// canned ACLs, grants which none of them match are not implemented.
 func (server *minioAPI) putBucketACLHandler(w http.ResponseWriter, req *http.Request) {
 	acceptsContentType := getContentType(req)
-	// read from 'x-amz-acl'
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	grants, ok := getRequestACLGrants(w, req, acceptsContentType)
+	if !ok {
+		return
+	}
+	// canned acls from 'x-amz-acl' are kept by name, the grants of a body have to match one of them
 	aclType := getACLType(req)
+	if req.Header.Get("x-amz-acl") == "" {
+		aclType = getBucketACLType(grants)
+	}
 	if aclType == unsupportedACLType {
 		writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
 		return
@@ -282,6 +419,40 @@ func (server *minioAPI) putBucketACLHandler(w http.ResponseWriter, req *http.Req
 	}
 }
 
+// GET Bucket ACL
+// ----------
+// This implementation of the GET operation returns the grants of the canned ACL of a bucket.
+func (server *minioAPI) getBucketACLHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	bucketMetadata, err := server.driver.GetBucketMetadata(bucket)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			response := generateAccessControlPolicy(getCannedACLGrants(parseACLType(bucketMetadata.ACL.String())))
+			encodedSuccessResponse := encodeSuccessResponse(response, acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
 // HEAD Bucket
 // ----------
 // This operation is useful to determine if a bucket exists.
@@ -318,3 +489,1394 @@ func (server *minioAPI) headBucketHandler(w http.ResponseWriter, req *http.Reque
 		}
 	}
 }
+
+// POST Bucket (POST Object)
+// -------------------------
+// This operation adds an object to the bucket through an HTML form, the upload is
+// authorized by a signed policy document sent along as one of the form fields.
+func (server *minioAPI) postPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	if isRequestDelete(req.URL.Query()) {
+		server.deleteMultipleObjectsHandler(w, req)
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	reader, err := req.MultipartReader()
+	if err != nil {
+		writeErrorResponse(w, req, MalformedPOSTRequest, acceptsContentType, req.URL.Path)
+		return
+	}
+	form, filePart, err := parsePostForm(reader)
+	if err != nil {
+		writeErrorResponse(w, req, MalformedPOSTRequest, acceptsContentType, req.URL.Path)
+		return
+	}
+	form["bucket"] = bucket
+	if form["key"] == "" {
+		writeErrorResponse(w, req, MalformedPOSTRequest, acceptsContentType, req.URL.Path)
+		return
+	}
+	form["key"] = strings.Replace(form["key"], "${filename}", filePart.FileName(), -1)
+	if form["policy"] == "" {
+		writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
+		return
+	}
+	switch doesPolicySignatureMatch(server.users, form) {
+	case nil:
+	case errInvalidAccessKey:
+		writeErrorResponse(w, req, InvalidAccessKeyID, acceptsContentType, req.URL.Path)
+		return
+	case errSignatureMismatch:
+		writeErrorResponse(w, req, SignatureDoesNotMatch, acceptsContentType, req.URL.Path)
+		return
+	default:
+		writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
+		return
+	}
+	decision, err := server.getBucketPolicyDecision(req, bucket, form["key"], "s3:PutObject", getPostPolicyAccessKey(form))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if decision == policyDeny {
+		writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
+		return
+	}
+	policy, err := parsePostPolicy(form["policy"])
+	if err != nil {
+		writeErrorResponse(w, req, InvalidPolicyDocument, acceptsContentType, req.URL.Path)
+		return
+	}
+	if err := checkPostPolicy(policy, form); err != nil {
+		writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
+		return
+	}
+	// the acl form field takes the place of the 'x-amz-acl' header
+	aclType := parseACLType(form["acl"])
+	if aclType == unsupportedACLType {
+		writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		return
+	}
+
+	// drivers need the object size up front, spool the file to find it while enforcing the size limits
+	maxLength := int64(maxObjectSize)
+	if policy.contentLengthRange && policy.maxLength < maxLength {
+		maxLength = policy.maxLength
+	}
+	file, err := ioutil.TempFile("", "minio-post-")
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	size, err := io.Copy(file, io.LimitReader(filePart, maxLength+1))
+	if err != nil {
+		writeErrorResponse(w, req, IncompleteBody, acceptsContentType, req.URL.Path)
+		return
+	}
+	if size > maxLength {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	if policy.contentLengthRange && size < policy.minLength {
+		writeErrorResponse(w, req, EntityTooSmall, acceptsContentType, req.URL.Path)
+		return
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+
+	// form fields carry the same metadata a PUT would send as headers
+	header := make(http.Header)
+	for name, value := range form {
+		header.Set(name, value)
+	}
+	object := form["key"]
+	attributes := drivers.ObjectAttributes{
+		Metadata: getObjectMetadataHeaders(header),
+		ACL:      getStoredObjectGrants(getCannedACLGrants(aclType)),
+	}
+	objectMetadata, err := server.driver.CreateObject(bucket, object, form["content-type"], "", size, file, attributes)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			w.Header().Set("ETag", objectMetadata.Md5)
+			setObjectVersionHeaders(w, objectMetadata)
+			writePostPolicyResponse(w, req, form, bucket, object, objectMetadata.Md5, acceptsContentType)
+			server.notifyEvent(req, "s3:ObjectCreated:Post", bucket, object)
+		}
+	case drivers.ObjectExists:
+		{
+			writeErrorResponse(w, req, MethodNotAllowed, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	case drivers.EntityTooLarge:
+		{
+			writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// POST Bucket (Delete Multiple Objects)
+// -------------------------------------
+// This operation deletes up to 1000 objects of a bucket in a single request. Content-MD5 of the
+// request body is mandatory, in quiet mode the response only lists the keys which failed.
+func (server *minioAPI) deleteMultipleObjectsHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	md5 := strings.TrimSpace(req.Header.Get("Content-MD5"))
+	if md5 == "" {
+		writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+		return
+	}
+	expectedMD5Sum, err := base64.StdEncoding.DecodeString(md5)
+	if err != nil {
+		writeErrorResponse(w, req, InvalidDigest, acceptsContentType, req.URL.Path)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxDeleteRequestSize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(body) > maxDeleteRequestSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	actualMD5Sum := cryptomd5.Sum(body)
+	if !bytes.Equal(expectedMD5Sum, actualMD5Sum[:]) {
+		writeErrorResponse(w, req, BadDigest, acceptsContentType, req.URL.Path)
+		return
+	}
+	deleteRequest := DeleteObjectsRequest{}
+	if err := xml.Unmarshal(body, &deleteRequest); err != nil {
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(deleteRequest.Object) == 0 || len(deleteRequest.Object) > maxDeleteList {
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+
+	// keys denied by the bucket policy are reported without being passed to the driver, versions
+	// are removed permanently one by one
+	accessKey := getRequestAccessKey(req)
+	errs := make(map[ObjectIdentifier]error)
+	var keys []string
+	var versions []ObjectIdentifier
+	for _, object := range deleteRequest.Object {
+		action := "s3:DeleteObject"
+		if object.VersionID != "" {
+			action = "s3:DeleteObjectVersion"
+		}
+		decision, err := server.getBucketPolicyDecision(req, bucket, object.Key, action, accessKey)
+		if err != nil {
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+			return
+		}
+		switch {
+		case decision == policyDeny:
+			errs[object] = errPolicyDenied
+		case object.VersionID != "":
+			versions = append(versions, object)
+		default:
+			keys = append(keys, object.Key)
+		}
+	}
+	keyErrs, err := server.driver.DeleteObjects(bucket, keys)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			deleted := make(map[ObjectIdentifier]DeletedObject)
+			// in a bucket with versioning the deletes left delete markers behind
+			status, err := server.driver.GetBucketVersioning(bucket)
+			for _, key := range keys {
+				object := ObjectIdentifier{Key: key}
+				errs[object] = keyErrs[key]
+				if keyErrs[key] != nil || err != nil || status == "" {
+					continue
+				}
+				if metadata, err := server.driver.GetObjectVersionMetadata(bucket, key, ""); err == nil && metadata.IsDeleteMarker {
+					deleted[object] = DeletedObject{
+						Key:                   key,
+						DeleteMarker:          true,
+						DeleteMarkerVersionID: metadata.VersionID,
+					}
+				}
+			}
+			for _, object := range versions {
+				metadata, err := server.driver.GetObjectVersionMetadata(bucket, object.Key, object.VersionID)
+				if err == nil {
+					err = server.driver.DeleteObjectVersion(bucket, object.Key, object.VersionID)
+				}
+				errs[object] = err
+				if err == nil && metadata.IsDeleteMarker {
+					deleted[object] = DeletedObject{
+						Key:                   object.Key,
+						VersionID:             object.VersionID,
+						DeleteMarker:          true,
+						DeleteMarkerVersionID: object.VersionID,
+					}
+				}
+			}
+			response := generateDeleteObjectsResult(deleteRequest, deleted, errs)
+			encodedSuccessResponse := encodeSuccessResponse(response, acceptsContentType)
+			// write headers
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			// write body
+			w.Write(encodedSuccessResponse)
+			for _, key := range keys {
+				if keyErrs[key] == nil {
+					server.notifyRemoveEvent(req, bucket, key)
+				}
+			}
+			for _, object := range versions {
+				if errs[object] == nil {
+					server.notifyEvent(req, "s3:ObjectRemoved:Delete", bucket, object.Key)
+				}
+			}
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Bucket policy
+// -----------------
+// This implementation of the PUT operation sets the access policy of a bucket, replacing any previous one.
+func (server *minioAPI) putBucketPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	policy, err := ioutil.ReadAll(io.LimitReader(req.Body, maxBucketPolicySize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(policy) > maxBucketPolicySize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	if _, err := parseBucketPolicy(policy, bucket); err != nil {
+		writeErrorResponse(w, req, MalformedPolicy, acceptsContentType, req.URL.Path)
+		return
+	}
+	err = server.driver.SetBucketResource(bucket, bucketPolicyResource, policy)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket policy
+// -----------------
+// This implementation of the GET operation returns the access policy document of a bucket as it was set.
+func (server *minioAPI) getBucketPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	policy, err := server.driver.GetBucketResource(bucket, bucketPolicyResource)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			setCommonHeaders(w, "application/json", len(policy))
+			w.Write(policy)
+		}
+	case drivers.BucketResourceNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucketPolicy, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// DELETE Bucket policy
+// --------------------
+// This implementation of the DELETE operation removes the access policy of a bucket.
+func (server *minioAPI) deleteBucketPolicyHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	err := server.driver.DeleteBucketResource(bucket, bucketPolicyResource)
+	switch iodine.ToError(err).(type) {
+	case nil, drivers.BucketResourceNotFound:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Bucket cors
+// ---------------
+// This implementation of the PUT operation sets the cross-origin resource sharing configuration of a bucket,
+// replacing any existing configuration.
+func (server *minioAPI) putBucketCORSHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxCORSConfigurationSize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(body) > maxCORSConfigurationSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	config, err := parseCORSConfiguration(body)
+	if err != nil {
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	// keep the configuration in its canonical form, it is parsed again for every cross-origin request
+	data, err := xml.Marshal(config)
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	err = server.driver.SetBucketResource(bucket, bucketCORSResource, data)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			writeSuccessResponse(w, acceptsContentType)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket cors
+// ---------------
+// This implementation of the GET operation returns the cross-origin resource sharing configuration of a bucket.
+func (server *minioAPI) getBucketCORSHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	config, err := getBucketCORSConfiguration(server.driver, bucket)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			encodedSuccessResponse := encodeSuccessResponse(config, acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketResourceNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchCORSConfiguration, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// DELETE Bucket cors
+// ------------------
+// This implementation of the DELETE operation removes the cross-origin resource sharing configuration of a bucket.
+func (server *minioAPI) deleteBucketCORSHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	err := server.driver.DeleteBucketResource(bucket, bucketCORSResource)
+	switch iodine.ToError(err).(type) {
+	case nil, drivers.BucketResourceNotFound:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// OPTIONS Object
+// --------------
+// This implementation of the OPTIONS operation answers CORS preflight requests for a bucket or object,
+// the requested origin, method and headers must be allowed by one of the rules of the bucket.
+func (server *minioAPI) preflightHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	origin := req.Header.Get("Origin")
+	method := req.Header.Get("Access-Control-Request-Method")
+	if origin == "" || method == "" {
+		writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+		return
+	}
+
+	config, err := getBucketCORSConfiguration(server.driver, bucket)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			headers := getCORSRequestHeaders(req)
+			rule := config.getMatchingRule(origin, method, headers)
+			if rule == nil {
+				writeErrorResponse(w, req, AccessForbidden, acceptsContentType, req.URL.Path)
+				return
+			}
+			setCORSHeaders(w, rule, origin)
+			if len(headers) > 0 {
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(headers, ", "))
+			}
+			w.Header().Add("Vary", "Origin")
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.WriteHeader(http.StatusOK)
+		}
+	case drivers.BucketResourceNotFound:
+		{
+			writeErrorResponse(w, req, AccessForbidden, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Bucket lifecycle
+// --------------------
+// This implementation of the PUT operation sets the lifecycle configuration of a bucket, replacing any
+// existing configuration. Its rules are applied by the lifecycle worker of the server.
+func (server *minioAPI) putBucketLifecycleHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxLifecycleConfigurationSize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(body) > maxLifecycleConfigurationSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	config, err := parseLifecycleConfiguration(body)
+	if err != nil {
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	data, err := xml.Marshal(config)
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	err = server.driver.SetBucketResource(bucket, bucketLifecycleResource, data)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			writeSuccessResponse(w, acceptsContentType)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket lifecycle
+// --------------------
+// This implementation of the GET operation returns the lifecycle configuration of a bucket.
+func (server *minioAPI) getBucketLifecycleHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	config, err := getBucketLifecycleConfiguration(server.driver, bucket)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			encodedSuccessResponse := encodeSuccessResponse(config, acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketResourceNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchLifecycleConfiguration, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// DELETE Bucket lifecycle
+// -----------------------
+// This implementation of the DELETE operation removes the lifecycle configuration of a bucket.
+func (server *minioAPI) deleteBucketLifecycleHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	err := server.driver.DeleteBucketResource(bucket, bucketLifecycleResource)
+	switch iodine.ToError(err).(type) {
+	case nil, drivers.BucketResourceNotFound:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Bucket versioning
+// ---------------------
+// This implementation of the PUT operation enables or suspends versioning of a bucket. Once enabled
+// versioning can only be suspended, objects written while suspended replace the null version.
+func (server *minioAPI) putBucketVersioningHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	config := new(VersioningConfiguration)
+	if err := xml.NewDecoder(io.LimitReader(req.Body, maxVersioningConfigurationSize)).Decode(config); err != nil {
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	if config.Status != drivers.VersioningEnabled && config.Status != drivers.VersioningSuspended {
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	err := server.driver.SetBucketVersioning(bucket, config.Status)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			writeSuccessResponse(w, acceptsContentType)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.APINotImplemented:
+		{
+			writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket versioning
+// ---------------------
+// This implementation of the GET operation returns the versioning status of a bucket, without
+// status for buckets which never had versioning enabled.
+func (server *minioAPI) getBucketVersioningHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	status, err := server.driver.GetBucketVersioning(bucket)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			encodedSuccessResponse := encodeSuccessResponse(VersioningConfiguration{Status: status}, acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket versions
+// -------------------
+// This implementation of the GET operation returns some or all (up to 1000) of the object versions
+// and delete markers in a bucket, sorted by key and newest first for every key.
+func (server *minioAPI) listObjectVersionsHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	resources := getBucketVersionResources(req.URL.Query())
+	if !isValidEncodingType(resources.EncodingType) {
+		writeErrorResponse(w, req, InvalidArgument, acceptsContentType, req.URL.Path)
+		return
+	}
+	if resources.Maxkeys <= 0 || resources.Maxkeys > maxObjectList {
+		resources.Maxkeys = maxObjectList
+	}
+	// a version id marker is meaningless without a key marker
+	if resources.VersionIDMarker != "" && resources.Marker == "" {
+		writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	versions, resources, err := server.driver.ListObjectVersions(bucket, resources)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			response := generateListVersionsResponse(bucket, versions, resources)
+			encodedSuccessResponse := encodeSuccessResponse(response, acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.APINotImplemented:
+		{
+			writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Bucket tagging
+// ------------------
+// This implementation of the PUT operation sets the tags of a bucket, replacing any existing tags.
+func (server *minioAPI) putBucketTaggingHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxTaggingSize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(body) > maxTaggingSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	tags, err := parseTagging(body, maxBucketTags)
+	switch err {
+	case nil:
+	case errInvalidTag:
+		writeErrorResponse(w, req, InvalidTag, acceptsContentType, req.URL.Path)
+		return
+	default:
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	data, err := xml.Marshal(generateTagging(tags))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	err = server.driver.SetBucketResource(bucket, bucketTaggingResource, data)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			writeSuccessResponse(w, acceptsContentType)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket tagging
+// ------------------
+// This implementation of the GET operation returns the tags of a bucket.
+func (server *minioAPI) getBucketTaggingHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	data, err := server.driver.GetBucketResource(bucket, bucketTaggingResource)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			tags, err := parseTagging(data, maxBucketTags)
+			if err != nil {
+				log.Error.Println(iodine.New(err, nil))
+				writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+				return
+			}
+			encodedSuccessResponse := encodeSuccessResponse(generateTagging(tags), acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketResourceNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchTagSet, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// DELETE Bucket tagging
+// ---------------------
+// This implementation of the DELETE operation removes the tags of a bucket.
+func (server *minioAPI) deleteBucketTaggingHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	err := server.driver.DeleteBucketResource(bucket, bucketTaggingResource)
+	switch iodine.ToError(err).(type) {
+	case nil, drivers.BucketResourceNotFound:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Bucket website
+// ------------------
+// This implementation of the PUT operation sets the website configuration of a bucket, replacing
+// any existing configuration.
+func (server *minioAPI) putBucketWebsiteHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxWebsiteConfigurationSize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(body) > maxWebsiteConfigurationSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	config, err := parseWebsiteConfiguration(body)
+	if err != nil {
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	data, err := xml.Marshal(config)
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	err = server.driver.SetBucketResource(bucket, bucketWebsiteResource, data)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			writeSuccessResponse(w, acceptsContentType)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket website
+// ------------------
+// This implementation of the GET operation returns the website configuration of a bucket.
+func (server *minioAPI) getBucketWebsiteHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	config, err := getBucketWebsiteConfiguration(server.driver, bucket)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			encodedSuccessResponse := encodeSuccessResponse(config, acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketResourceNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchWebsiteConfiguration, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// DELETE Bucket website
+// ---------------------
+// This implementation of the DELETE operation removes the website configuration of a bucket.
+func (server *minioAPI) deleteBucketWebsiteHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	err := server.driver.DeleteBucketResource(bucket, bucketWebsiteResource)
+	switch iodine.ToError(err).(type) {
+	case nil, drivers.BucketResourceNotFound:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Bucket notification
+// -----------------------
+// This implementation of the PUT operation sets the webhooks events of a bucket are delivered to,
+// an empty configuration turns off the notifications of the bucket.
+func (server *minioAPI) putBucketNotificationHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxNotificationConfigurationSize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(body) > maxNotificationConfigurationSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	config, err := parseNotificationConfiguration(body)
+	if err != nil {
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(config.WebhookConfigurations) == 0 {
+		err = server.driver.DeleteBucketResource(bucket, bucketNotificationResource)
+		if _, ok := iodine.ToError(err).(drivers.BucketResourceNotFound); ok {
+			err = nil
+		}
+	} else {
+		var data []byte
+		data, err = xml.Marshal(config)
+		if err != nil {
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+			return
+		}
+		err = server.driver.SetBucketResource(bucket, bucketNotificationResource, data)
+	}
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			writeSuccessResponse(w, acceptsContentType)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket notification
+// -----------------------
+// This implementation of the GET operation returns the notification configuration of a bucket, a
+// bucket without notifications has an empty configuration.
+func (server *minioAPI) getBucketNotificationHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	config, err := getBucketNotificationConfiguration(server.driver, bucket)
+	if _, ok := iodine.ToError(err).(drivers.BucketResourceNotFound); ok {
+		// verify the bucket exists before answering with an empty configuration
+		_, err = server.driver.GetBucketMetadata(bucket)
+		config = &NotificationConfiguration{}
+	}
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			encodedSuccessResponse := encodeSuccessResponse(config, acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Bucket logging
+// ------------------
+// This implementation of the PUT operation sets the bucket and key prefix the access log of a bucket
+// is written to, a status without LoggingEnabled turns off the access log.
+func (server *minioAPI) putBucketLoggingHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxLoggingConfigurationSize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(body) > maxLoggingConfigurationSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	config, err := parseLoggingConfiguration(body)
+	if err != nil {
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	if config.LoggingEnabled == nil {
+		err = server.driver.DeleteBucketResource(bucket, bucketLoggingResource)
+		if _, ok := iodine.ToError(err).(drivers.BucketResourceNotFound); ok {
+			err = nil
+		}
+	} else {
+		_, err = server.driver.GetBucketMetadata(config.LoggingEnabled.TargetBucket)
+		switch iodine.ToError(err).(type) {
+		case nil:
+		case drivers.BucketNotFound, drivers.BucketNameInvalid:
+			{
+				writeErrorResponse(w, req, InvalidTargetBucketForLogging, acceptsContentType, req.URL.Path)
+				return
+			}
+		default:
+			{
+				log.Error.Println(iodine.New(err, nil))
+				writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+				return
+			}
+		}
+		var data []byte
+		data, err = xml.Marshal(config)
+		if err != nil {
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+			return
+		}
+		err = server.driver.SetBucketResource(bucket, bucketLoggingResource, data)
+	}
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			writeSuccessResponse(w, acceptsContentType)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket logging
+// ------------------
+// This implementation of the GET operation returns the logging status of a bucket, a bucket without
+// access log has an empty status.
+func (server *minioAPI) getBucketLoggingHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	config, err := getBucketLoggingConfiguration(server.driver, bucket)
+	if _, ok := iodine.ToError(err).(drivers.BucketResourceNotFound); ok {
+		// verify the bucket exists before answering with an empty status
+		_, err = server.driver.GetBucketMetadata(bucket)
+		config = &BucketLoggingStatus{}
+	}
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			encodedSuccessResponse := encodeSuccessResponse(config, acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Bucket (List Objects) Version 2
+// -----------------------------------
+// This implementation of the GET operation returns some or all (up to 1000) of the objects in a bucket,
+// like the original listing. Pages are chained through opaque continuation tokens instead of markers,
+// and the owner of objects is only returned when fetch-owner is set.
+func (server *minioAPI) listObjectsV2Handler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	resources, err := getBucketResourcesV2(req.URL.Query())
+	if err != nil || !isValidEncodingType(resources.EncodingType) {
+		writeErrorResponse(w, req, InvalidArgument, acceptsContentType, req.URL.Path)
+		return
+	}
+	if resources.Maxkeys == 0 {
+		resources.Maxkeys = maxObjectList
+	}
+	token := req.URL.Query().Get("continuation-token")
+	startAfter := req.URL.Query().Get("start-after")
+	fetchOwner := req.URL.Query().Get("fetch-owner") == "true"
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	objects, resources, err := server.driver.ListObjects(bucket, resources)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			response := generateListObjectsV2Response(bucket, token, startAfter, fetchOwner, objects, resources)
+			encodedSuccessResponse := encodeSuccessResponse(response, acceptsContentType)
+			// write headers
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			// write body
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNotFound, drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
@@ -23,6 +23,17 @@ const (
 	maxObjectList = 1000
 )
 
+// Limits of a multi-object delete request
+const (
+	maxDeleteList        = 1000
+	maxDeleteRequestSize = 2 * 1024 * 1024
+)
+
+// Limit size of a versioning configuration
+const (
+	maxVersioningConfigurationSize = 4 * 1024
+)
+
 // ListObjectsResponse - format for list objects response
 type ListObjectsResponse struct {
 	XMLName xml.Name `xml:"http://doc.s3.amazonaws.com/2006-03-01 ListBucketResult" json:"-"`
@@ -53,13 +64,54 @@ type ListObjectsResponse struct {
 	Prefix     string
 }
 
+// ListObjectsV2Response - format for list objects response of ?list-type=2, pages are chained through
+// opaque continuation tokens
+type ListObjectsV2Response struct {
+	XMLName xml.Name `xml:"http://doc.s3.amazonaws.com/2006-03-01 ListBucketResult" json:"-"`
+
+	Name                  string
+	Prefix                string
+	StartAfter            string `xml:",omitempty"`
+	ContinuationToken     string `xml:",omitempty"`
+	NextContinuationToken string `xml:",omitempty"`
+	KeyCount              int
+	MaxKeys               int
+	Delimiter             string `xml:",omitempty"`
+	EncodingType          string `xml:",omitempty"`
+	IsTruncated           bool
+
+	Contents       []*Object
+	CommonPrefixes []*CommonPrefix
+}
+
+// ListVersionsResponse - format for list object versions response
+type ListVersionsResponse struct {
+	XMLName xml.Name `xml:"http://doc.s3.amazonaws.com/2006-03-01 ListVersionsResult" json:"-"`
+
+	Name                string
+	Prefix              string
+	KeyMarker           string
+	VersionIDMarker     string `xml:"VersionIdMarker"`
+	NextKeyMarker       string `xml:",omitempty"`
+	NextVersionIDMarker string `xml:"NextVersionIdMarker,omitempty"`
+	MaxKeys             int
+	Delimiter           string `xml:",omitempty"`
+	EncodingType        string `xml:",omitempty"`
+	IsTruncated         bool
+
+	// versions and delete markers in the order they are listed in
+	Versions       []interface{}
+	CommonPrefixes []*CommonPrefix
+}
+
 // ListPartsResponse - format for list parts response
 type ListPartsResponse struct {
 	XMLName xml.Name `xml:"http://doc.s3.amazonaws.com/2006-03-01 ListPartsResult" json:"-"`
 
-	Bucket   string
-	Key      string
-	UploadID string `xml:"UploadId"`
+	Bucket       string
+	Key          string
+	UploadID     string `xml:"UploadId"`
+	EncodingType string `xml:",omitempty"`
 
 	Initiator Initiator
 	Owner     Owner
@@ -140,12 +192,42 @@ type Object struct {
 	LastModified string
 	Size         int64
 
+	// left out of ?list-type=2 listings unless fetch-owner is asked for
+	Owner *Owner `xml:",omitempty"`
+
+	// The class of storage used to store the object.
+	StorageClass string
+}
+
+// ObjectVersion container for a version in ListVersionsResponse
+type ObjectVersion struct {
+	XMLName xml.Name `xml:"Version" json:"-"`
+
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified string
+	ETag         string
+	Size         int64
+
 	Owner Owner
 
 	// The class of storage used to store the object.
 	StorageClass string
 }
 
+// DeleteMarkerEntry container for a delete marker in ListVersionsResponse
+type DeleteMarkerEntry struct {
+	XMLName xml.Name `xml:"DeleteMarker" json:"-"`
+
+	Key          string
+	VersionID    string `xml:"VersionId"`
+	IsLatest     bool
+	LastModified string
+
+	Owner Owner
+}
+
 // Initiator inherit from Owner struct, fields are same
 type Initiator Owner
 
@@ -186,19 +268,256 @@ type CompleteMultipartUploadResult struct {
 	ETag     string
 }
 
+// CopyObjectResult container for copy object response
+type CopyObjectResult struct {
+	XMLName xml.Name `xml:"http://doc.s3.amazonaws.com/2006-03-01 CopyObjectResult" json:"-"`
+
+	LastModified string // time string of format "2006-01-02T15:04:05.000Z"
+	ETag         string
+}
+
+// PostResponse container for POST object upload response with success_action_status 201
+type PostResponse struct {
+	XMLName xml.Name `xml:"PostResponse" json:"-"`
+
+	Location string
+	Bucket   string
+	Key      string
+	ETag     string
+}
+
+// DeleteObjectsRequest container for multi-object delete request
+type DeleteObjectsRequest struct {
+	Quiet  bool
+	Object []ObjectIdentifier
+}
+
+// ObjectIdentifier key of an object to be deleted, along with the version to be removed permanently if set
+type ObjectIdentifier struct {
+	Key       string
+	VersionID string `xml:"VersionId,omitempty"`
+}
+
+// DeleteObjectsResult container for multi-object delete response
+type DeleteObjectsResult struct {
+	XMLName xml.Name `xml:"http://doc.s3.amazonaws.com/2006-03-01 DeleteResult" json:"-"`
+
+	Deleted []DeletedObject `xml:",omitempty"`
+	Error   []DeleteError   `xml:",omitempty"`
+}
+
+// DeletedObject key of an object removed by multi-object delete, with the delete marker a delete in
+// a bucket with versioning created or the delete marker version removed
+type DeletedObject struct {
+	Key                   string
+	VersionID             string `xml:"VersionId,omitempty"`
+	DeleteMarker          bool   `xml:",omitempty"`
+	DeleteMarkerVersionID string `xml:"DeleteMarkerVersionId,omitempty"`
+}
+
+// DeleteError key of an object multi-object delete failed to remove, with the reason
+type DeleteError struct {
+	Key       string
+	VersionID string `xml:"VersionId,omitempty"`
+	Code      string
+	Message   string
+}
+
+// CORSConfiguration container for the cross-origin resource sharing rules of a bucket
+type CORSConfiguration struct {
+	XMLName xml.Name `xml:"CORSConfiguration" json:"-"`
+
+	CORSRule []CORSRule
+}
+
+// CORSRule origins, methods and headers allowed for cross-origin requests
+type CORSRule struct {
+	ID            string `xml:",omitempty"`
+	AllowedOrigin []string
+	AllowedMethod []string
+	AllowedHeader []string `xml:",omitempty"`
+	MaxAgeSeconds int      `xml:",omitempty"`
+	ExposeHeader  []string `xml:",omitempty"`
+}
+
+// LifecycleConfiguration container for the lifecycle rules of a bucket
+type LifecycleConfiguration struct {
+	XMLName xml.Name `xml:"LifecycleConfiguration" json:"-"`
+
+	Rule []LifecycleRule
+}
+
+// LifecycleRule actions applied to the objects under a prefix
+type LifecycleRule struct {
+	ID     string           `xml:",omitempty"`
+	Prefix string           `xml:",omitempty"`
+	Filter *LifecycleFilter `xml:",omitempty"`
+	Status string
+
+	Expiration                     *LifecycleExpiration            `xml:",omitempty"`
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:",omitempty"`
+}
+
+// LifecycleFilter selects the objects a lifecycle rule applies to
+type LifecycleFilter struct {
+	Prefix string
+}
+
+// LifecycleExpiration objects expire either a number of days after creation or on a date
+type LifecycleExpiration struct {
+	Days int    `xml:",omitempty"`
+	Date string `xml:",omitempty"`
+}
+
+// AbortIncompleteMultipartUpload days after initiation incomplete multipart uploads are aborted
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int
+}
+
+// VersioningConfiguration container for the versioning status of a bucket
+type VersioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration" json:"-"`
+
+	Status string `xml:",omitempty"`
+}
+
+// Tagging container for the tag set of a bucket or an object
+type Tagging struct {
+	XMLName xml.Name `xml:"Tagging" json:"-"`
+
+	TagSet TagSet
+}
+
+// TagSet container for tags
+type TagSet struct {
+	Tag []Tag
+}
+
+// Tag key and value of a tag
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// WebsiteConfiguration container for the static website configuration of a bucket
+type WebsiteConfiguration struct {
+	XMLName xml.Name `xml:"WebsiteConfiguration" json:"-"`
+
+	RedirectAllRequestsTo *RedirectAllRequestsTo `xml:",omitempty"`
+	IndexDocument         *IndexDocument         `xml:",omitempty"`
+	ErrorDocument         *ErrorDocument         `xml:",omitempty"`
+	RoutingRules          []RoutingRule          `xml:"RoutingRules>RoutingRule,omitempty"`
+}
+
+// RedirectAllRequestsTo host every request to a website is redirected to
+type RedirectAllRequestsTo struct {
+	HostName string
+	Protocol string `xml:",omitempty"`
+}
+
+// IndexDocument suffix appended to requests for the website root or a directory
+type IndexDocument struct {
+	Suffix string
+}
+
+// ErrorDocument key of the object returned when a website request fails
+type ErrorDocument struct {
+	Key string
+}
+
+// RoutingRule redirect applied to website requests matching its condition
+type RoutingRule struct {
+	Condition *RoutingRuleCondition `xml:",omitempty"`
+	Redirect  RoutingRuleRedirect
+}
+
+// RoutingRuleCondition key prefix and error code a routing rule applies to
+type RoutingRuleCondition struct {
+	KeyPrefixEquals             string `xml:",omitempty"`
+	HTTPErrorCodeReturnedEquals int    `xml:"HttpErrorCodeReturnedEquals,omitempty"`
+}
+
+// RoutingRuleRedirect target of a routing rule, the key is replaced either whole or by prefix
+type RoutingRuleRedirect struct {
+	HostName             string  `xml:",omitempty"`
+	Protocol             string  `xml:",omitempty"`
+	HTTPRedirectCode     int     `xml:"HttpRedirectCode,omitempty"`
+	ReplaceKeyPrefixWith *string `xml:",omitempty"`
+	ReplaceKeyWith       string  `xml:",omitempty"`
+}
+
+// NotificationConfiguration container for the event notifications of a bucket
+type NotificationConfiguration struct {
+	XMLName xml.Name `xml:"NotificationConfiguration" json:"-"`
+
+	WebhookConfigurations []WebhookConfiguration `xml:"WebhookConfiguration"`
+}
+
+// WebhookConfiguration events of a bucket delivered to an http endpoint
+type WebhookConfiguration struct {
+	ID       string `xml:"Id,omitempty"`
+	Endpoint string
+	Events   []string            `xml:"Event"`
+	Filter   *NotificationFilter `xml:",omitempty"`
+}
+
+// NotificationFilter limits the events of a webhook to keys with a prefix or suffix
+type NotificationFilter struct {
+	FilterRules []FilterRule `xml:"S3Key>FilterRule"`
+}
+
+// FilterRule a "prefix" or "suffix" the key of an event must have
+type FilterRule struct {
+	Name  string
+	Value string
+}
+
+// BucketLoggingStatus container for the access log configuration of a bucket
+type BucketLoggingStatus struct {
+	XMLName xml.Name `xml:"BucketLoggingStatus" json:"-"`
+
+	LoggingEnabled *LoggingEnabled `xml:",omitempty"`
+}
+
+// LoggingEnabled bucket and key prefix the access log of a bucket is written to
+type LoggingEnabled struct {
+	TargetBucket string
+	TargetPrefix string
+}
+
+// AccessControlPolicy container for the owner and the grants of a bucket or object ACL
+type AccessControlPolicy struct {
+	XMLName xml.Name `xml:"AccessControlPolicy" json:"-"`
+
+	Owner             Owner
+	AccessControlList AccessControlList
+}
+
+// AccessControlList container for grants
+type AccessControlList struct {
+	Grant []Grant
+}
+
+// Grant permission given to a grantee
+type Grant struct {
+	Grantee    Grantee
+	Permission string
+}
+
+// Grantee user or group of a grant, named by its canonical id, email address or group uri
+type Grantee struct {
+	XMLNS        string `xml:"xmlns:xsi,attr"`
+	Type         string `xml:"xsi:type,attr"`
+	ID           string `xml:",omitempty"`
+	DisplayName  string `xml:",omitempty"`
+	EmailAddress string `xml:",omitempty"`
+	URI          string `xml:",omitempty"`
+}
+
 // List of not implemented bucket queries
 var notimplementedBucketResourceNames = map[string]bool{
-	"policy":         true,
-	"cors":           true,
-	"lifecycle":      true,
 	"location":       true,
-	"logging":        true,
-	"notification":   true,
-	"tagging":        true,
-	"versions":       true,
 	"requestPayment": true,
-	"versioning":     true,
-	"website":        true,
 }
 
 // List of not implemented object queries
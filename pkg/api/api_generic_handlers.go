@@ -18,11 +18,16 @@ package api
 
 import (
 	"errors"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/minio/minio/pkg/api/config"
+	"github.com/minio/minio/pkg/storage/drivers"
 	"github.com/minio/minio/pkg/utils/crypto/keys"
 )
 
@@ -36,18 +41,37 @@ type timeHandler struct {
 
 type validateAuthHandler struct {
 	handler http.Handler
+	users   *userStore
 }
 
 type resourceHandler struct {
 	handler http.Handler
 }
 
+type corsHandler struct {
+	handler http.Handler
+	driver  drivers.Driver
+}
+
+type accessLogHandler struct {
+	handler http.Handler
+	logger  *accessLogger
+}
+
+type virtualHostHandler struct {
+	handler http.Handler
+	domain  string
+}
+
 type auth struct {
 	prefix        string
 	credential    string
 	signedheaders string
 	signature     string
 	accessKey     string
+	presigned     bool
+	date          time.Time
+	expires       time.Duration
 }
 
 const (
@@ -55,15 +79,44 @@ const (
 )
 
 const (
-	authHeaderPrefix = "AWS4-HMAC-SHA256"
+	authHeaderPrefix   = "AWS4-HMAC-SHA256"
+	authHeaderPrefixV2 = "AWS"
 )
 
+// maximum validity of a presigned request is 7 days
+const maxPresignedExpiry = 7 * 24 * time.Hour
+
+var errInvalidAccessKey = errors.New("Invalid access key")
+
+// strip signature v2 auth "AWS <accesskey>:<signature>" from authorization header
+func stripAuthV2(authHeader string) (*auth, error) {
+	authFields := strings.Fields(authHeader)
+	if len(authFields) != 2 {
+		return nil, errors.New("Missing fields in Auth header")
+	}
+	credentials := strings.Split(authFields[1], ":")
+	if len(credentials) != 2 {
+		return nil, errors.New("Missing fields in Auth header")
+	}
+	a := new(auth)
+	a.prefix = authHeaderPrefixV2
+	a.accessKey = credentials[0]
+	a.signature = credentials[1]
+	if !keys.IsValidAccessKey(a.accessKey) {
+		return nil, errInvalidAccessKey
+	}
+	return a, nil
+}
+
 // strip auth from authorization header
 func stripAuth(r *http.Request) (*auth, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
 		return nil, errors.New("Missing auth header")
 	}
+	if strings.HasPrefix(authHeader, authHeaderPrefixV2+" ") {
+		return stripAuthV2(authHeader)
+	}
 	a := new(auth)
 	authFields := strings.Split(authHeader, ",")
 	if len(authFields) != 3 {
@@ -88,16 +141,109 @@ func stripAuth(r *http.Request) (*auth, error) {
 	if len(signature) != 2 {
 		return nil, errors.New("Missing fields in Auth header")
 	}
+	a.prefix = authHeaderPrefix
 	a.credential = credentials[1]
 	a.signedheaders = signedheaders[1]
 	a.signature = signature[1]
 	a.accessKey = strings.Split(a.credential, "/")[0]
 	if !keys.IsValidAccessKey(a.accessKey) {
-		return nil, errors.New("Invalid access key")
+		return nil, errInvalidAccessKey
+	}
+	return a, nil
+}
+
+// isPresignedRequest - verify if the request is authenticated through its query string
+func isPresignedRequest(r *http.Request) bool {
+	return r.URL.Query().Get("X-Amz-Algorithm") != ""
+}
+
+// strip presigned auth from the X-Amz-* query parameters
+func stripPresignedAuth(r *http.Request) (*auth, error) {
+	query := r.URL.Query()
+	if query.Get("X-Amz-Algorithm") != authHeaderPrefix {
+		return nil, errors.New("Unsupported algorithm in query")
+	}
+	a := new(auth)
+	a.prefix = authHeaderPrefix
+	a.presigned = true
+	a.credential = query.Get("X-Amz-Credential")
+	a.signedheaders = query.Get("X-Amz-SignedHeaders")
+	a.signature = query.Get("X-Amz-Signature")
+	if a.credential == "" || a.signedheaders == "" || a.signature == "" {
+		return nil, errors.New("Missing fields in query")
+	}
+	date, err := time.Parse(timeFormat, query.Get("X-Amz-Date"))
+	if err != nil {
+		return nil, errors.New("Invalid date in query")
+	}
+	a.date = date
+	expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires <= 0 || time.Duration(expires)*time.Second > maxPresignedExpiry {
+		return nil, errors.New("Invalid expiry in query")
+	}
+	a.expires = time.Duration(expires) * time.Second
+	a.accessKey = strings.Split(a.credential, "/")[0]
+	if !keys.IsValidAccessKey(a.accessKey) {
+		return nil, errInvalidAccessKey
 	}
 	return a, nil
 }
 
+// userStore - configured users kept in memory, the config file is read again only when an unknown
+// access key shows up after it changed
+type userStore struct {
+	lock    *sync.RWMutex
+	users   map[string]config.User
+	modTime time.Time
+}
+
+func newUserStore() *userStore {
+	return &userStore{lock: new(sync.RWMutex)}
+}
+
+// reload - read the users of the config file if it changed since it was read last
+func (u *userStore) reload() error {
+	var conf = config.Config{}
+	if err := conf.SetupConfig(); err != nil {
+		return err
+	}
+	stat, err := os.Stat(conf.ConfigFile)
+	if err != nil {
+		return err
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if u.users != nil && stat.ModTime().Equal(u.modTime) {
+		return nil
+	}
+	if err := conf.ReadConfig(); err != nil {
+		return err
+	}
+	u.users = conf.Users
+	u.modTime = stat.ModTime()
+	return nil
+}
+
+// getSecretKey - secret key of the configured user owning the access key
+func (u *userStore) getSecretKey(accessKey string) (string, error) {
+	u.lock.RLock()
+	user, ok := u.users[accessKey]
+	u.lock.RUnlock()
+	if ok {
+		return user.SecretKey, nil
+	}
+	if err := u.reload(); err != nil {
+		return "", err
+	}
+	u.lock.RLock()
+	defer u.lock.RUnlock()
+	user, ok = u.users[accessKey]
+	if !ok {
+		return "", errInvalidAccessKey
+	}
+	return user.SecretKey, nil
+}
+
 func getDate(req *http.Request) (time.Time, error) {
 	amzDate := req.Header.Get("X-Amz-Date")
 	switch {
@@ -141,6 +287,9 @@ func (h contentTypeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.handler.ServeHTTP(w, r)
 }
 
+// maxClockSkew - how far the date of a request may be off from the server's time
+const maxClockSkew = 5 * time.Minute
+
 func timeValidityHandler(h http.Handler) http.Handler {
 	return timeHandler{h}
 }
@@ -160,9 +309,7 @@ func (h timeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			writeErrorResponse(w, r, RequestTimeTooSkewed, acceptsContentType, r.URL.Path)
 			return
 		}
-		duration := time.Since(date)
-		minutes := time.Duration(5) * time.Minute
-		if duration.Minutes() > minutes.Minutes() {
+		if time.Since(date) > maxClockSkew {
 			writeErrorResponse(w, r, RequestTimeTooSkewed, acceptsContentType, r.URL.Path)
 			return
 		}
@@ -171,39 +318,170 @@ func (h timeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // validate auth header handler is wrapper handler used for API request validation with authorization header.
-// Current authorization layer supports S3's standard HMAC based signature request.
-func validateAuthHeaderHandler(h http.Handler) http.Handler {
-	return validateAuthHandler{h}
+// Current authorization layer supports S3's standard HMAC based signature request, both signature v4 and the
+// legacy signature v2, along with signature v4 presigned requests authenticated through the query string.
+func validateAuthHeaderHandler(h http.Handler, users *userStore) http.Handler {
+	return validateAuthHandler{h, users}
 }
 
 // validate auth header handler ServeHTTP() wrapper
 func (h validateAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	acceptsContentType := getContentType(r)
-	_, err := stripAuth(r)
-	switch err.(type) {
-	case nil:
-		var conf = config.Config{}
-		if err := conf.SetupConfig(); err != nil {
-			writeErrorResponse(w, r, InternalError, acceptsContentType, r.URL.Path)
+	var auth *auth
+	var err error
+	switch {
+	case r.Header.Get("Authorization") != "":
+		auth, err = stripAuth(r)
+		switch err {
+		case nil:
+		case errInvalidAccessKey:
+			writeErrorResponse(w, r, InvalidAccessKeyID, acceptsContentType, r.URL.Path)
+			return
+		default:
+			writeErrorResponse(w, r, AuthorizationHeaderMalformed, acceptsContentType, r.URL.Path)
 			return
 		}
-		if err := conf.ReadConfig(); err != nil {
-			writeErrorResponse(w, r, InternalError, acceptsContentType, r.URL.Path)
+	case isPresignedRequest(r):
+		auth, err = stripPresignedAuth(r)
+		switch err {
+		case nil:
+		case errInvalidAccessKey:
+			writeErrorResponse(w, r, InvalidAccessKeyID, acceptsContentType, r.URL.Path)
+			return
+		default:
+			writeErrorResponse(w, r, AuthorizationQueryParametersError, acceptsContentType, r.URL.Path)
+			return
+		}
+		if time.Now().UTC().After(auth.date.Add(auth.expires)) {
+			writeErrorResponse(w, r, ExpiredPresignRequest, acceptsContentType, r.URL.Path)
+			return
+		}
+		// a presigned request only becomes valid at its date
+		if auth.date.After(time.Now().UTC().Add(maxClockSkew)) {
+			writeErrorResponse(w, r, RequestNotReadyYet, acceptsContentType, r.URL.Path)
 			return
 		}
-		// uncomment this when we have webcli
-		// _, ok := conf.Users[auth.accessKey]
-		//if !ok {
-		//	writeErrorResponse(w, r, AccessDenied, acceptsContentType, r.URL.Path)
-		//	return
-		//}
-		// Success
-		h.handler.ServeHTTP(w, r)
 	default:
 		// control reaches here, we should just send the request up the stack - internally
 		// individual calls will validate themselves against un-authenticated requests
 		h.handler.ServeHTTP(w, r)
+		return
+	}
+	secretKey, err := h.users.getSecretKey(auth.accessKey)
+	switch err {
+	case nil:
+	case errInvalidAccessKey:
+		writeErrorResponse(w, r, InvalidAccessKeyID, acceptsContentType, r.URL.Path)
+		return
+	default:
+		writeErrorResponse(w, r, InternalError, acceptsContentType, r.URL.Path)
+		return
+	}
+	var signatureErr error
+	switch {
+	case auth.presigned:
+		signatureErr = doesPresignedSignatureV4Match(secretKey, r, auth)
+	case auth.prefix == authHeaderPrefixV2:
+		signatureErr = doesSignatureV2Match(secretKey, r, auth)
+	default:
+		signatureErr = doesSignatureV4Match(secretKey, r, auth)
+	}
+	switch signatureErr {
+	case nil:
+		// Success
+		if auth.prefix == authHeaderPrefix && !auth.presigned {
+			verifyPayload(r)
+		}
+		h.handler.ServeHTTP(w, r)
+	case errMissingContentHash, errInvalidContentHash:
+		writeErrorResponse(w, r, InvalidArgument, acceptsContentType, r.URL.Path)
+	case errMalformedCredential:
+		writeErrorResponse(w, r, AuthorizationHeaderMalformed, acceptsContentType, r.URL.Path)
+	case errHostNotSigned, errUnsignedHeaders, errMissingSignedHeader:
+		writeErrorResponse(w, r, AccessDenied, acceptsContentType, r.URL.Path)
+	case errSignatureMismatch:
+		writeErrorResponse(w, r, SignatureDoesNotMatch, acceptsContentType, r.URL.Path)
+	default:
+		// request date could not be parsed, there is no way of knowing if this is a valid request
+		writeErrorResponse(w, r, RequestTimeTooSkewed, acceptsContentType, r.URL.Path)
+	}
+}
+
+// cors handler is wrapper handler adding the Access-Control-* headers to responses of cross-origin requests,
+// whose Origin and method are allowed by the CORS configuration of the bucket. Preflight requests are
+// answered by their own handler.
+func corsResponseHandler(h http.Handler, driver drivers.Driver) http.Handler {
+	return corsHandler{h, driver}
+}
+
+// cors handler ServeHTTP() wrapper
+func (h corsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	bucket := getRequestBucket(r)
+	if origin != "" && bucket != "" && r.Method != "OPTIONS" {
+		// requests to buckets without a usable configuration are served as they are
+		if config, err := getBucketCORSConfiguration(h.driver, bucket); err == nil {
+			if rule := config.getMatchingRule(origin, r.Method, nil); rule != nil {
+				setCORSHeaders(w, rule, origin)
+			}
+			w.Header().Add("Vary", "Origin")
+		}
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// access log handler is wrapper handler recording the requests to buckets with logging enabled, in the
+// S3 server access log format, records are written into the target buckets by the access logger.
+func serverAccessLogHandler(h http.Handler, logger *accessLogger) http.Handler {
+	return accessLogHandler{h, logger}
+}
+
+// access log handler ServeHTTP() wrapper
+func (h accessLogHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now().UTC()
+	logWriter := &accessLogWriter{ResponseWriter: w}
+	h.handler.ServeHTTP(logWriter, r)
+	h.logger.record(r, logWriter, start)
+}
+
+// virtual host handler is wrapper handler rewriting virtual hosted style requests to "<bucket>.<domain>"
+// into path style requests, ahead of routing. The path the client signed stays in the request URI.
+func virtualHostStyleHandler(h http.Handler, domain string) http.Handler {
+	return virtualHostHandler{h, strings.ToLower(domain)}
+}
+
+// virtual host handler ServeHTTP() wrapper
+func (h virtualHostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, ok := getVirtualHostBucket(r.Host, h.domain)
+	if !ok {
+		h.handler.ServeHTTP(w, r)
+		return
+	}
+	if !drivers.IsValidBucket(bucket) {
+		writeErrorResponse(w, r, InvalidBucketName, getContentType(r), r.URL.Path)
+		return
+	}
+	if r.URL.Path == "/" || r.URL.Path == "" {
+		r.URL.Path = "/" + bucket
+	} else {
+		r.URL.Path = "/" + bucket + r.URL.Path
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// getVirtualHostBucket - bucket named by the host of a virtual hosted style request, the port is ignored
+func getVirtualHostBucket(host, domain string) (string, bool) {
+	if domain == "" {
+		return "", false
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	if !strings.HasSuffix(strings.ToLower(host), "."+domain) {
+		return "", false
 	}
+	bucket := host[:len(host)-len(domain)-1]
+	return bucket, bucket != ""
 }
 
 // Ignore resources handler is wrapper handler used for API request resource validation
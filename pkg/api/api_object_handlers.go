@@ -17,9 +17,14 @@
 package api
 
 import (
+	"io"
+	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
+	"strings"
 
 	"encoding/xml"
 
@@ -44,45 +49,106 @@ func (server *minioAPI) getObjectHandler(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
+	if isRequestTagging(req.URL.Query()) {
+		server.getObjectTaggingHandler(w, req)
+		return
+	}
+
+	if isRequestACL(req.URL.Query()) {
+		server.getObjectACLHandler(w, req)
+		return
+	}
+
 	var object, bucket string
 	vars := mux.Vars(req)
 	bucket = vars["bucket"]
 	object = vars["object"]
+	versionID := req.URL.Query().Get("versionId")
+
+	// response headers are overridden for signed requests only
+	isResponseOverride := isRequestResponseOverride(req.URL.Query())
+	if isResponseOverride && getRequestAccessKey(req) == "" {
+		writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+		return
+	}
 
-	metadata, err := server.driver.GetObjectMetadata(bucket, object)
+	metadata, err := server.getRequestedObjectMetadata(bucket, object, versionID)
 	switch iodine.ToError(err).(type) {
 	case nil: // success
 		{
-			httpRange, err := getRequestedRange(req, metadata.Size)
+			// a delete marker has no data
+			if metadata.IsDeleteMarker {
+				setObjectVersionHeaders(w, metadata)
+				writeErrorResponse(w, req, MethodNotAllowed, acceptsContentType, req.URL.Path)
+				return
+			}
+			if isResponseOverride {
+				metadata = getResponseOverrideMetadata(metadata, req.URL.Query())
+			}
+			switch evaluatePreconditions(req.Header, "", metadata) {
+			case preconditionFailed:
+				writeErrorResponse(w, req, PreconditionFailed, acceptsContentType, req.URL.Path)
+				return
+			case preconditionNotModified:
+				writeNotModifiedResponse(w, metadata)
+				return
+			}
+			ranges, err := getRequestedRanges(req, metadata.Size)
 			if err != nil {
 				writeErrorResponse(w, req, InvalidRange, acceptsContentType, req.URL.Path)
 				return
 			}
-			switch httpRange.start == 0 && httpRange.length == 0 {
-			case true:
+			switch len(ranges) {
+			case 0:
 				setObjectHeaders(w, metadata)
-				if _, err := server.driver.GetObject(w, bucket, object); err != nil {
+				if _, err := server.getRequestedObject(w, bucket, object, versionID); err != nil {
 					// unable to write headers, we've already printed data. Just close the connection.
 					log.Error.Println(iodine.New(err, nil))
 				}
-			case false:
+			case 1:
+				httpRange := ranges[0]
 				metadata.Size = httpRange.length
 				setRangeObjectHeaders(w, metadata, httpRange)
 				w.WriteHeader(http.StatusPartialContent)
-				if _, err := server.driver.GetPartialObject(w, bucket, object, httpRange.start, httpRange.length); err != nil {
+				if _, err := server.getRequestedPartialObject(w, bucket, object, versionID, httpRange); err != nil {
 					// unable to write headers, we've already printed data. Just close the connection.
 					log.Error.Println(iodine.New(err, nil))
 				}
+			default:
+				multipartWriter := multipart.NewWriter(w)
+				setMultipartRangeObjectHeaders(w, metadata, ranges, multipartWriter.Boundary())
+				w.WriteHeader(http.StatusPartialContent)
+				for _, httpRange := range ranges {
+					part, err := multipartWriter.CreatePart(httpRange.getMimeHeader(metadata.ContentType))
+					if err != nil {
+						log.Error.Println(iodine.New(err, nil))
+						return
+					}
+					if _, err := server.getRequestedPartialObject(part, bucket, object, versionID, httpRange); err != nil {
+						// unable to write headers, we've already printed data. Just close the connection.
+						log.Error.Println(iodine.New(err, nil))
+						return
+					}
+				}
+				multipartWriter.Close()
 			}
 		}
 	case drivers.ObjectNotFound:
 		{
 			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
 		}
+	case drivers.ObjectVersionNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchVersion, acceptsContentType, req.URL.Path)
+		}
 	case drivers.ObjectNameInvalid:
 		{
 			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
 		}
+	case drivers.APINotImplemented:
+		{
+			writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		}
 	default:
 		{
 			log.Error.Println(iodine.New(err, nil))
@@ -91,6 +157,30 @@ func (server *minioAPI) getObjectHandler(w http.ResponseWriter, req *http.Reques
 	}
 }
 
+// getRequestedObjectMetadata - metadata of the latest object, or of the version asked for with ?versionId
+func (server *minioAPI) getRequestedObjectMetadata(bucket, object, versionID string) (drivers.ObjectMetadata, error) {
+	if versionID == "" {
+		return server.driver.GetObjectMetadata(bucket, object)
+	}
+	return server.driver.GetObjectVersionMetadata(bucket, object, versionID)
+}
+
+// getRequestedObject - data of the latest object, or of the version asked for with ?versionId
+func (server *minioAPI) getRequestedObject(w io.Writer, bucket, object, versionID string) (int64, error) {
+	if versionID == "" {
+		return server.driver.GetObject(w, bucket, object)
+	}
+	return server.driver.GetObjectVersion(w, bucket, object, versionID)
+}
+
+// getRequestedPartialObject - a range of the latest object, or of the version asked for with ?versionId
+func (server *minioAPI) getRequestedPartialObject(w io.Writer, bucket, object, versionID string, httpRange *httpRange) (int64, error) {
+	if versionID == "" {
+		return server.driver.GetPartialObject(w, bucket, object, httpRange.start, httpRange.length)
+	}
+	return server.driver.GetPartialObjectVersion(w, bucket, object, versionID, httpRange.start, httpRange.length)
+}
+
 // HEAD Object
 // -----------
 // The HEAD operation retrieves metadata from an object without returning the object itself.
@@ -106,10 +196,27 @@ func (server *minioAPI) headObjectHandler(w http.ResponseWriter, req *http.Reque
 	bucket = vars["bucket"]
 	object = vars["object"]
 
-	metadata, err := server.driver.GetObjectMetadata(bucket, object)
+	metadata, err := server.getRequestedObjectMetadata(bucket, object, req.URL.Query().Get("versionId"))
 	switch iodine.ToError(err).(type) {
 	case nil:
 		{
+			if metadata.IsDeleteMarker {
+				error := getErrorCode(MethodNotAllowed)
+				setObjectVersionHeaders(w, metadata)
+				w.Header().Set("Server", "Minio")
+				w.WriteHeader(error.HTTPStatusCode)
+				return
+			}
+			switch evaluatePreconditions(req.Header, "", metadata) {
+			case preconditionFailed:
+				error := getErrorCode(PreconditionFailed)
+				w.Header().Set("Server", "Minio")
+				w.WriteHeader(error.HTTPStatusCode)
+				return
+			case preconditionNotModified:
+				writeNotModifiedResponse(w, metadata)
+				return
+			}
 			setObjectHeaders(w, metadata)
 			w.WriteHeader(http.StatusOK)
 		}
@@ -119,12 +226,24 @@ func (server *minioAPI) headObjectHandler(w http.ResponseWriter, req *http.Reque
 			w.Header().Set("Server", "Minio")
 			w.WriteHeader(error.HTTPStatusCode)
 		}
+	case drivers.ObjectVersionNotFound:
+		{
+			error := getErrorCode(NoSuchVersion)
+			w.Header().Set("Server", "Minio")
+			w.WriteHeader(error.HTTPStatusCode)
+		}
 	case drivers.ObjectNameInvalid:
 		{
 			error := getErrorCode(NoSuchKey)
 			w.Header().Set("Server", "Minio")
 			w.WriteHeader(error.HTTPStatusCode)
 		}
+	case drivers.APINotImplemented:
+		{
+			error := getErrorCode(NotImplemented)
+			w.Header().Set("Server", "Minio")
+			w.WriteHeader(error.HTTPStatusCode)
+		}
 	default:
 		{
 			log.Error.Println(iodine.New(err, nil))
@@ -145,6 +264,16 @@ func (server *minioAPI) putObjectHandler(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
+	if isRequestTagging(req.URL.Query()) {
+		server.putObjectTaggingHandler(w, req)
+		return
+	}
+
+	if isRequestACL(req.URL.Query()) {
+		server.putObjectACLHandler(w, req)
+		return
+	}
+
 	var object, bucket string
 	vars := mux.Vars(req)
 	bucket = vars["bucket"]
@@ -162,6 +291,14 @@ func (server *minioAPI) putObjectHandler(w http.ResponseWriter, req *http.Reques
 		writeErrorResponse(w, req, MissingContentLength, acceptsContentType, req.URL.Path)
 		return
 	}
+	/// size of a chunk signed payload is the size of the decoded data
+	if isRequestStreamingSignatureV4(req) {
+		size = req.Header.Get("X-Amz-Decoded-Content-Length")
+		if size == "" {
+			writeErrorResponse(w, req, MissingContentLength, acceptsContentType, req.URL.Path)
+			return
+		}
+	}
 	/// maximum Upload size for objects in a single operation
 	if isMaxObjectSize(size) {
 		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
@@ -182,13 +319,36 @@ func (server *minioAPI) putObjectHandler(w http.ResponseWriter, req *http.Reques
 		writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
 		return
 	}
-	calculatedMD5, err := server.driver.CreateObject(bucket, object, "", md5, sizeInt64, req.Body)
+	payload, ok := getRequestPayload(w, req, server.users, sizeInt64, acceptsContentType)
+	if !ok {
+		return
+	}
+	var tags map[string]string
+	if tagging := req.Header.Get("X-Amz-Tagging"); tagging != "" {
+		if tags, err = parseTaggingHeader(tagging); err != nil {
+			writeErrorResponse(w, req, InvalidTag, acceptsContentType, req.URL.Path)
+			return
+		}
+	}
+	aclType := getACLType(req)
+	if aclType == unsupportedACLType {
+		writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		return
+	}
+	contentType := req.Header.Get("Content-Type")
+	attributes := drivers.ObjectAttributes{
+		Metadata: getObjectMetadataHeaders(req.Header),
+		Tags:     tags,
+		ACL:      getStoredObjectGrants(getCannedACLGrants(aclType)),
+	}
+	objectMetadata, err := server.driver.CreateObject(bucket, object, contentType, md5, sizeInt64, payload, attributes)
 	switch iodine.ToError(err).(type) {
 	case nil:
 		{
-			w.Header().Set("ETag", calculatedMD5)
+			w.Header().Set("ETag", objectMetadata.Md5)
+			setObjectVersionHeaders(w, objectMetadata)
 			writeSuccessResponse(w, acceptsContentType)
-
+			server.notifyEvent(req, "s3:ObjectCreated:Put", bucket, object)
 		}
 	case drivers.ObjectExists:
 		{
@@ -206,6 +366,18 @@ func (server *minioAPI) putObjectHandler(w http.ResponseWriter, req *http.Reques
 		{
 			writeErrorResponse(w, req, InvalidDigest, acceptsContentType, req.URL.Path)
 		}
+	case chunkSignatureMismatch:
+		{
+			writeErrorResponse(w, req, SignatureDoesNotMatch, acceptsContentType, req.URL.Path)
+		}
+	case malformedChunkedEncoding:
+		{
+			writeErrorResponse(w, req, IncompleteBody, acceptsContentType, req.URL.Path)
+		}
+	case contentSHA256Mismatch:
+		{
+			writeErrorResponse(w, req, XAmzContentSHA256Mismatch, acceptsContentType, req.URL.Path)
+		}
 	default:
 		{
 			log.Error.Println(iodine.New(err, nil))
@@ -214,6 +386,210 @@ func (server *minioAPI) putObjectHandler(w http.ResponseWriter, req *http.Reques
 	}
 }
 
+// PUT Object - Copy
+// -----------------
+// This implementation of the PUT operation creates a copy of an object that is already
+// stored, the source object is named by the x-amz-copy-source header as "/bucket/object",
+// an earlier version of it as "/bucket/object?versionId=version".
+func (server *minioAPI) copyObjectHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	var object, bucket string
+	vars := mux.Vars(req)
+	bucket = vars["bucket"]
+	object = vars["object"]
+
+	sourceBucket, sourceObject, sourceVersionID, ok := getCopySource(req.Header.Get("X-Amz-Copy-Source"))
+	if !ok {
+		writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+		return
+	}
+	// an empty content type keeps the content type and metadata of the source object
+	var contentType string
+	var objectMetadata map[string]string
+	switch req.Header.Get("X-Amz-Metadata-Directive") {
+	case "", "COPY":
+		// copying an object onto itself is only allowed when its metadata is replaced, or to restore
+		// an earlier version of it
+		if sourceBucket == bucket && sourceObject == object && sourceVersionID == "" {
+			writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+			return
+		}
+	case "REPLACE":
+		contentType = req.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		objectMetadata = getObjectMetadataHeaders(req.Header)
+	default:
+		writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+		return
+	}
+	aclType := getACLType(req)
+	if aclType == unsupportedACLType {
+		writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		return
+	}
+
+	// without credentials the source has to be readable by everyone as well, which is checked
+	// first so that a missing source object is not told apart from one that may not be read
+	if getRequestAccessKey(req) == "" {
+		readable, err := server.isReadableByEveryone(req, sourceBucket, sourceObject)
+		switch iodine.ToError(err).(type) {
+		case nil:
+		case drivers.BucketNotFound:
+			{
+				writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+				return
+			}
+		case drivers.BucketNameInvalid:
+			{
+				writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+				return
+			}
+		default:
+			{
+				log.Error.Println(iodine.New(err, nil))
+				writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+				return
+			}
+		}
+		if !readable {
+			writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
+			return
+		}
+	}
+
+	sourceMetadata, err := server.getRequestedObjectMetadata(sourceBucket, sourceObject, sourceVersionID)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			// a delete marker has no data to copy
+			if sourceMetadata.IsDeleteMarker {
+				writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+				return
+			}
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+			return
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+			return
+		}
+	case drivers.ObjectNotFound, drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+			return
+		}
+	case drivers.ObjectVersionNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchVersion, acceptsContentType, req.URL.Path)
+			return
+		}
+	case drivers.APINotImplemented:
+		{
+			writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+			return
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+			return
+		}
+	}
+	// a failed copy source condition is never reported as not modified
+	if evaluatePreconditions(req.Header, "X-Amz-Copy-Source-", sourceMetadata) != preconditionsMet {
+		writeErrorResponse(w, req, PreconditionFailed, acceptsContentType, req.URL.Path)
+		return
+	}
+	/// maximum Upload size for objects in a single operation
+	if sourceMetadata.Size > maxObjectSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+
+	grants := getStoredObjectGrants(getCannedACLGrants(aclType))
+	metadata, err := server.copyRequestedObject(sourceBucket, sourceObject, sourceVersionID, bucket, object, contentType, objectMetadata, grants)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			response := generateCopyObjectResult(metadata)
+			encodedSuccessResponse := encodeSuccessResponse(response, acceptsContentType)
+			// write headers
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			setObjectVersionHeaders(w, metadata)
+			// drivers report the null version without a version id
+			copySourceVersionID := sourceMetadata.VersionID
+			if copySourceVersionID == "" {
+				copySourceVersionID = sourceVersionID
+			}
+			if copySourceVersionID != "" {
+				w.Header().Set("X-Amz-Copy-Source-Version-Id", copySourceVersionID)
+			}
+			// write body
+			w.Write(encodedSuccessResponse)
+			server.notifyEvent(req, "s3:ObjectCreated:Copy", bucket, object)
+		}
+	case drivers.ObjectExists:
+		{
+			writeErrorResponse(w, req, MethodNotAllowed, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectVersionNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchVersion, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// getCopySource - source bucket, object and version from the url encoded "/bucket/object?versionId=version" copy source,
+// without a versionId the latest version is copied
+func getCopySource(copySource string) (string, string, string, bool) {
+	sourceURL, err := url.Parse(copySource)
+	if err != nil {
+		return "", "", "", false
+	}
+	source := strings.SplitN(strings.TrimPrefix(sourceURL.Path, "/"), "/", 2)
+	if len(source) != 2 || source[0] == "" || source[1] == "" {
+		return "", "", "", false
+	}
+	return source[0], source[1], sourceURL.Query().Get("versionId"), true
+}
+
+// copyRequestedObject - copy the latest object, or the version asked for with the versionId of the copy source
+func (server *minioAPI) copyRequestedObject(sourceBucket, sourceObject, sourceVersionID, bucket, object, contentType string,
+	metadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	if sourceVersionID == "" {
+		return server.driver.CopyObject(sourceBucket, sourceObject, bucket, object, contentType, metadata, grants)
+	}
+	return server.driver.CopyObjectVersion(sourceBucket, sourceObject, sourceVersionID, bucket, object, contentType, metadata, grants)
+}
+
 /// Multipart API
 
 // New multipart upload
@@ -233,7 +609,26 @@ func (server *minioAPI) newMultipartUploadHandler(w http.ResponseWriter, req *ht
 	vars := mux.Vars(req)
 	bucket = vars["bucket"]
 	object = vars["object"]
-	uploadID, err := server.driver.NewMultipartUpload(bucket, object, "")
+	var tags map[string]string
+	if tagging := req.Header.Get("X-Amz-Tagging"); tagging != "" {
+		var err error
+		if tags, err = parseTaggingHeader(tagging); err != nil {
+			writeErrorResponse(w, req, InvalidTag, acceptsContentType, req.URL.Path)
+			return
+		}
+	}
+	aclType := getACLType(req)
+	if aclType == unsupportedACLType {
+		writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		return
+	}
+	// content type, metadata, tags and grants are kept for the object the upload completes
+	attributes := drivers.ObjectAttributes{
+		Metadata: getObjectMetadataHeaders(req.Header),
+		Tags:     tags,
+		ACL:      getStoredObjectGrants(getCannedACLGrants(aclType)),
+	}
+	uploadID, err := server.driver.NewMultipartUpload(bucket, object, req.Header.Get("Content-Type"), attributes)
 	switch iodine.ToError(err).(type) {
 	case nil:
 		{
@@ -277,6 +672,14 @@ func (server *minioAPI) putObjectPartHandler(w http.ResponseWriter, req *http.Re
 		writeErrorResponse(w, req, MissingContentLength, acceptsContentType, req.URL.Path)
 		return
 	}
+	/// size of a chunk signed payload is the size of the decoded data
+	if isRequestStreamingSignatureV4(req) {
+		size = req.Header.Get("X-Amz-Decoded-Content-Length")
+		if size == "" {
+			writeErrorResponse(w, req, MissingContentLength, acceptsContentType, req.URL.Path)
+			return
+		}
+	}
 
 	/// maximum Upload size for multipart objects in a single operation
 	if isMaxObjectSize(size) {
@@ -289,6 +692,10 @@ func (server *minioAPI) putObjectPartHandler(w http.ResponseWriter, req *http.Re
 		writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
 		return
 	}
+	payload, ok := getRequestPayload(w, req, server.users, sizeInt64, acceptsContentType)
+	if !ok {
+		return
+	}
 
 	vars := mux.Vars(req)
 	bucket := vars["bucket"]
@@ -301,7 +708,7 @@ func (server *minioAPI) putObjectPartHandler(w http.ResponseWriter, req *http.Re
 	if err != nil {
 		writeErrorResponse(w, req, InvalidPart, acceptsContentType, req.URL.Path)
 	}
-	calculatedMD5, err := server.driver.CreateObjectPart(bucket, object, uploadID, partID, "", md5, sizeInt64, req.Body)
+	calculatedMD5, err := server.driver.CreateObjectPart(bucket, object, uploadID, partID, "", md5, sizeInt64, payload)
 	switch iodine.ToError(err).(type) {
 	case nil:
 		{
@@ -329,6 +736,18 @@ func (server *minioAPI) putObjectPartHandler(w http.ResponseWriter, req *http.Re
 		{
 			writeErrorResponse(w, req, InvalidDigest, acceptsContentType, req.URL.Path)
 		}
+	case chunkSignatureMismatch:
+		{
+			writeErrorResponse(w, req, SignatureDoesNotMatch, acceptsContentType, req.URL.Path)
+		}
+	case malformedChunkedEncoding:
+		{
+			writeErrorResponse(w, req, IncompleteBody, acceptsContentType, req.URL.Path)
+		}
+	case contentSHA256Mismatch:
+		{
+			writeErrorResponse(w, req, XAmzContentSHA256Mismatch, acceptsContentType, req.URL.Path)
+		}
 	default:
 		{
 			log.Error.Println(iodine.New(err, nil))
@@ -379,6 +798,10 @@ func (server *minioAPI) listObjectPartsHandler(w http.ResponseWriter, req *http.
 	}
 
 	objectResourcesMetadata := getObjectResources(req.URL.Query())
+	if !isValidEncodingType(objectResourcesMetadata.EncodingType) {
+		writeErrorResponse(w, req, InvalidArgument, acceptsContentType, req.URL.Path)
+		return
+	}
 	if objectResourcesMetadata.MaxParts == 0 {
 		objectResourcesMetadata.MaxParts = maxPartsList
 	}
@@ -441,16 +864,18 @@ func (server *minioAPI) completeMultipartUploadHandler(w http.ResponseWriter, re
 		partMap[part.PartNumber] = part.ETag
 	}
 
-	etag, err := server.driver.CompleteMultipartUpload(bucket, object, objectResourcesMetadata.UploadID, partMap)
+	metadata, err := server.driver.CompleteMultipartUpload(bucket, object, objectResourcesMetadata.UploadID, partMap)
 	switch iodine.ToError(err).(type) {
 	case nil:
 		{
-			response := generateCompleteMultpartUploadResult(bucket, object, "", etag)
+			response := generateCompleteMultpartUploadResult(bucket, object, "", metadata.Md5)
 			encodedSuccessResponse := encodeSuccessResponse(response, acceptsContentType)
 			// write headers
 			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			setObjectVersionHeaders(w, metadata)
 			// write body
 			w.Write(encodedSuccessResponse)
+			server.notifyEvent(req, "s3:ObjectCreated:CompleteMultipartUpload", bucket, object)
 		}
 	case drivers.InvalidUploadID:
 		{
@@ -466,14 +891,390 @@ func (server *minioAPI) completeMultipartUploadHandler(w http.ResponseWriter, re
 
 /// Delete API
 
-// Delete bucket
+// DELETE Bucket
+// -------------
+// The DELETE operation removes a bucket, all objects and in-progress multipart uploads must be removed first.
 func (server *minioAPI) deleteBucketHandler(w http.ResponseWriter, req *http.Request) {
-	error := getErrorCode(NotImplemented)
-	w.WriteHeader(error.HTTPStatusCode)
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	if isRequestBucketPolicy(req.URL.Query()) {
+		server.deleteBucketPolicyHandler(w, req)
+		return
+	}
+
+	if isRequestBucketCORS(req.URL.Query()) {
+		server.deleteBucketCORSHandler(w, req)
+		return
+	}
+
+	if isRequestBucketLifecycle(req.URL.Query()) {
+		server.deleteBucketLifecycleHandler(w, req)
+		return
+	}
+
+	if isRequestTagging(req.URL.Query()) {
+		server.deleteBucketTaggingHandler(w, req)
+		return
+	}
+	if isRequestBucketWebsite(req.URL.Query()) {
+		server.deleteBucketWebsiteHandler(w, req)
+		return
+	}
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+
+	err := server.driver.DeleteBucket(bucket)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotEmpty:
+		{
+			writeErrorResponse(w, req, BucketNotEmpty, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
 }
 
-// Delete object
+// DELETE Object
+// -------------
+// The DELETE operation removes an object, deleting an object which does not exist is not an error.
 func (server *minioAPI) deleteObjectHandler(w http.ResponseWriter, req *http.Request) {
-	error := getErrorCode(NotImplemented)
-	w.WriteHeader(error.HTTPStatusCode)
+	acceptsContentType := getContentType(req)
+	// verify if this operation is allowed
+	if !server.isValidOp(w, req, acceptsContentType) {
+		return
+	}
+
+	var object, bucket string
+	vars := mux.Vars(req)
+	bucket = vars["bucket"]
+	object = vars["object"]
+
+	if isRequestTagging(req.URL.Query()) {
+		server.deleteObjectTaggingHandler(w, req)
+		return
+	}
+
+	if versionID := req.URL.Query().Get("versionId"); versionID != "" {
+		server.deleteObjectVersionHandler(w, req, bucket, object, versionID)
+		return
+	}
+
+	err := server.driver.DeleteObject(bucket, object)
+	switch iodine.ToError(err).(type) {
+	case nil, drivers.ObjectNotFound:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			// in a bucket with versioning the delete left a delete marker behind
+			if status, err := server.driver.GetBucketVersioning(bucket); err == nil && status != "" {
+				if metadata, err := server.driver.GetObjectVersionMetadata(bucket, object, ""); err == nil {
+					setObjectVersionHeaders(w, metadata)
+				}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			if err == nil {
+				server.notifyRemoveEvent(req, bucket, object)
+			}
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// DELETE Object version
+// ---------------------
+// This implementation of the DELETE operation permanently removes a version of an object, removing
+// a delete marker which is the latest version makes the version before it the latest again.
+func (server *minioAPI) deleteObjectVersionHandler(w http.ResponseWriter, req *http.Request, bucket, object, versionID string) {
+	acceptsContentType := getContentType(req)
+
+	metadata, err := server.driver.GetObjectVersionMetadata(bucket, object, versionID)
+	if err == nil {
+		err = server.driver.DeleteObjectVersion(bucket, object, versionID)
+	}
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.Header().Set("X-Amz-Version-Id", versionID)
+			if metadata.IsDeleteMarker {
+				w.Header().Set("X-Amz-Delete-Marker", "true")
+			}
+			w.WriteHeader(http.StatusNoContent)
+			server.notifyEvent(req, "s3:ObjectRemoved:Delete", bucket, object)
+		}
+	case drivers.ObjectNotFound, drivers.ObjectVersionNotFound:
+		{
+			// deleting a version which does not exist succeeds like deleting an object which does not
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.Header().Set("X-Amz-Version-Id", versionID)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	case drivers.APINotImplemented:
+		{
+			writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Object tagging
+// ------------------
+// This implementation of the GET operation returns the tags of an object, or of the version asked for with ?versionId.
+func (server *minioAPI) getObjectTaggingHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	metadata, err := server.getRequestedObjectMetadata(bucket, object, req.URL.Query().Get("versionId"))
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			if metadata.IsDeleteMarker {
+				setObjectVersionHeaders(w, metadata)
+				writeErrorResponse(w, req, MethodNotAllowed, acceptsContentType, req.URL.Path)
+				return
+			}
+			encodedSuccessResponse := encodeSuccessResponse(generateTagging(metadata.Tags), acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			setObjectVersionHeaders(w, metadata)
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNotFound, drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectVersionNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchVersion, acceptsContentType, req.URL.Path)
+		}
+	case drivers.APINotImplemented:
+		{
+			writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Object tagging
+// ------------------
+// This implementation of the PUT operation replaces the tags of an object.
+func (server *minioAPI) putObjectTaggingHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxTaggingSize+1))
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return
+	}
+	if len(body) > maxTaggingSize {
+		writeErrorResponse(w, req, EntityTooLarge, acceptsContentType, req.URL.Path)
+		return
+	}
+	tags, err := parseTagging(body, maxObjectTags)
+	switch err {
+	case nil:
+	case errInvalidTag:
+		writeErrorResponse(w, req, InvalidTag, acceptsContentType, req.URL.Path)
+		return
+	default:
+		writeErrorResponse(w, req, MalformedXML, acceptsContentType, req.URL.Path)
+		return
+	}
+	server.setObjectTags(w, req, bucket, object, tags, http.StatusOK)
+}
+
+// DELETE Object tagging
+// ---------------------
+// This implementation of the DELETE operation removes the tags of an object.
+func (server *minioAPI) deleteObjectTaggingHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	server.setObjectTags(w, req, vars["bucket"], vars["object"], nil, http.StatusNoContent)
+}
+
+// setObjectTags - replace the tags of an object and reply with the given status on success
+func (server *minioAPI) setObjectTags(w http.ResponseWriter, req *http.Request, bucket, object string, tags map[string]string, status int) {
+	acceptsContentType := getContentType(req)
+
+	err := server.driver.SetObjectTags(bucket, object, tags)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+			w.WriteHeader(status)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNotFound, drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// GET Object ACL
+// --------------
+// This implementation of the GET operation returns the grants of the ACL of an object, objects without
+// an ACL of their own are private.
+func (server *minioAPI) getObjectACLHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	metadata, err := server.getRequestedObjectMetadata(bucket, object, req.URL.Query().Get("versionId"))
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			if metadata.IsDeleteMarker {
+				setObjectVersionHeaders(w, metadata)
+				writeErrorResponse(w, req, MethodNotAllowed, acceptsContentType, req.URL.Path)
+				return
+			}
+			encodedSuccessResponse := encodeSuccessResponse(generateAccessControlPolicy(getObjectGrants(metadata.ACL)), acceptsContentType)
+			setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+			setObjectVersionHeaders(w, metadata)
+			w.Write(encodedSuccessResponse)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNotFound, drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectVersionNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchVersion, acceptsContentType, req.URL.Path)
+		}
+	case drivers.APINotImplemented:
+		{
+			writeErrorResponse(w, req, NotImplemented, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// PUT Object ACL
+// --------------
+// This implementation of the PUT operation replaces the ACL of an object, either by a canned ACL
+// through the 'x-amz-acl' header or by the grants of an access control policy body.
+func (server *minioAPI) putObjectACLHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := mux.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	grants, ok := getRequestACLGrants(w, req, acceptsContentType)
+	if !ok {
+		return
+	}
+	err := server.driver.SetObjectACL(bucket, object, getStoredObjectGrants(grants))
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			writeSuccessResponse(w, acceptsContentType)
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+		}
+	case drivers.ObjectNotFound, drivers.ObjectNameInvalid:
+		{
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
 }
@@ -18,9 +18,12 @@ package api
 
 import (
 	"net/http"
+	"net/url"
 	"sort"
 
+	"github.com/minio/minio/pkg/iodine"
 	"github.com/minio/minio/pkg/storage/drivers"
+	"github.com/minio/minio/pkg/utils/log"
 )
 
 // Reply date format
@@ -89,22 +92,146 @@ func generateListObjectsResponse(bucket string, objects []drivers.ObjectMetadata
 		content.ETag = "\"" + object.Md5 + "\""
 		content.Size = object.Size
 		content.StorageClass = "STANDARD"
-		content.Owner = owner
+		content.Owner = &owner
 		contents = append(contents, content)
 	}
 	sort.Sort(itemKey(contents))
-	// TODO - support EncodingType in xml decoding
+	encodingType := bucketResources.EncodingType
+	for _, content := range contents {
+		content.Key = encodeName(content.Key, encodingType)
+	}
+	data.Name = bucket
+	data.Contents = contents
+	data.MaxKeys = bucketResources.Maxkeys
+	data.Prefix = encodeName(bucketResources.Prefix, encodingType)
+	data.Delimiter = encodeName(bucketResources.Delimiter, encodingType)
+	data.EncodingType = encodingType
+	data.Marker = encodeName(bucketResources.Marker, encodingType)
+	data.NextMarker = encodeName(bucketResources.NextMarker, encodingType)
+	data.IsTruncated = bucketResources.IsTruncated
+	for _, prefix := range bucketResources.CommonPrefixes {
+		var prefixItem = &CommonPrefix{}
+		prefixItem.Prefix = encodeName(prefix, encodingType)
+		prefixes = append(prefixes, prefixItem)
+	}
+	data.CommonPrefixes = prefixes
+	return data
+}
+
+// generateListObjectsV2Response - objects of a ?list-type=2 listing, the owner of objects is only
+// reported when fetchOwner is set. A truncated listing continues after its last key or common prefix.
+func generateListObjectsV2Response(bucket, token, startAfter string, fetchOwner bool, objects []drivers.ObjectMetadata, bucketResources drivers.BucketResourcesMetadata) ListObjectsV2Response {
+	var contents []*Object
+	var prefixes []*CommonPrefix
+	var owner = Owner{}
+	var data = ListObjectsV2Response{}
+
+	owner.ID = "minio"
+	owner.DisplayName = "minio"
+
+	for _, object := range objects {
+		var content = &Object{}
+		if object.Key == "" {
+			continue
+		}
+		content.Key = object.Key
+		content.LastModified = object.Created.Format(iso8601Format)
+		content.ETag = "\"" + object.Md5 + "\""
+		content.Size = object.Size
+		content.StorageClass = "STANDARD"
+		if fetchOwner {
+			content.Owner = &owner
+		}
+		contents = append(contents, content)
+	}
+	sort.Sort(itemKey(contents))
+	// the token continues after the names as they are stored, before they are encoded
+	next := bucketResources.NextMarker
+	if len(contents) > 0 && contents[len(contents)-1].Key > next {
+		next = contents[len(contents)-1].Key
+	}
+	if len(bucketResources.CommonPrefixes) > 0 {
+		commonPrefixes := append([]string(nil), bucketResources.CommonPrefixes...)
+		sort.Strings(commonPrefixes)
+		if commonPrefixes[len(commonPrefixes)-1] > next {
+			next = commonPrefixes[len(commonPrefixes)-1]
+		}
+	}
+	encodingType := bucketResources.EncodingType
+	for _, content := range contents {
+		content.Key = encodeName(content.Key, encodingType)
+	}
+	for _, prefix := range bucketResources.CommonPrefixes {
+		var prefixItem = &CommonPrefix{}
+		prefixItem.Prefix = encodeName(prefix, encodingType)
+		prefixes = append(prefixes, prefixItem)
+	}
 	data.Name = bucket
 	data.Contents = contents
+	data.CommonPrefixes = prefixes
+	data.KeyCount = len(contents) + len(prefixes)
 	data.MaxKeys = bucketResources.Maxkeys
-	data.Prefix = bucketResources.Prefix
-	data.Delimiter = bucketResources.Delimiter
-	data.Marker = bucketResources.Marker
-	data.NextMarker = bucketResources.NextMarker
+	data.Prefix = encodeName(bucketResources.Prefix, encodingType)
+	data.Delimiter = encodeName(bucketResources.Delimiter, encodingType)
+	data.EncodingType = encodingType
+	data.StartAfter = encodeName(startAfter, encodingType)
+	data.ContinuationToken = token
+	data.IsTruncated = bucketResources.IsTruncated
+	if data.IsTruncated {
+		data.NextContinuationToken = encodeContinuationToken(next)
+	}
+	return data
+}
+
+// generateListVersionsResponse - versions and delete markers in listing order, the null version is reported as "null"
+func generateListVersionsResponse(bucket string, versions []drivers.ObjectMetadata, bucketResources drivers.BucketResourcesMetadata) ListVersionsResponse {
+	var prefixes []*CommonPrefix
+	var owner = Owner{}
+	var data = ListVersionsResponse{}
+	var encodingType = bucketResources.EncodingType
+
+	owner.ID = "minio"
+	owner.DisplayName = "minio"
+
+	for _, version := range versions {
+		versionID := version.VersionID
+		if versionID == "" {
+			versionID = drivers.NullVersionID
+		}
+		if version.IsDeleteMarker {
+			data.Versions = append(data.Versions, &DeleteMarkerEntry{
+				Key:          encodeName(version.Key, encodingType),
+				VersionID:    versionID,
+				IsLatest:     version.IsLatest,
+				LastModified: version.Created.Format(iso8601Format),
+				Owner:        owner,
+			})
+			continue
+		}
+		data.Versions = append(data.Versions, &ObjectVersion{
+			Key:          encodeName(version.Key, encodingType),
+			VersionID:    versionID,
+			IsLatest:     version.IsLatest,
+			LastModified: version.Created.Format(iso8601Format),
+			ETag:         "\"" + version.Md5 + "\"",
+			Size:         version.Size,
+			Owner:        owner,
+			StorageClass: "STANDARD",
+		})
+	}
+	data.Name = bucket
+	data.MaxKeys = bucketResources.Maxkeys
+	data.Prefix = encodeName(bucketResources.Prefix, encodingType)
+	data.Delimiter = encodeName(bucketResources.Delimiter, encodingType)
+	data.EncodingType = encodingType
+	data.KeyMarker = encodeName(bucketResources.Marker, encodingType)
+	data.VersionIDMarker = bucketResources.VersionIDMarker
+	data.NextKeyMarker = encodeName(bucketResources.NextMarker, encodingType)
+	data.NextVersionIDMarker = bucketResources.NextVersionIDMarker
 	data.IsTruncated = bucketResources.IsTruncated
 	for _, prefix := range bucketResources.CommonPrefixes {
 		var prefixItem = &CommonPrefix{}
-		prefixItem.Prefix = prefix
+		prefixItem.Prefix = encodeName(prefix, encodingType)
 		prefixes = append(prefixes, prefixItem)
 	}
 	data.CommonPrefixes = prefixes
@@ -130,12 +257,58 @@ func generateCompleteMultpartUploadResult(bucket, key, location, etag string) Co
 	}
 }
 
+// generateCopyObjectResult
+func generateCopyObjectResult(metadata drivers.ObjectMetadata) CopyObjectResult {
+	return CopyObjectResult{
+		LastModified: metadata.Created.Format(iso8601Format),
+		ETag:         "\"" + metadata.Md5 + "\"",
+	}
+}
+
+// generateDeleteObjectsResult - keys and versions which were not found count as deleted, quiet mode reports only the errors
+func generateDeleteObjectsResult(deleteRequest DeleteObjectsRequest, deleted map[ObjectIdentifier]DeletedObject, errs map[ObjectIdentifier]error) DeleteObjectsResult {
+	result := DeleteObjectsResult{}
+	for _, object := range deleteRequest.Object {
+		var deleteError Error
+		err := iodine.ToError(errs[object])
+		switch err.(type) {
+		case nil, drivers.ObjectNotFound, drivers.ObjectVersionNotFound:
+			if !deleteRequest.Quiet {
+				deletedObject, ok := deleted[object]
+				if !ok {
+					deletedObject = DeletedObject{Key: object.Key, VersionID: object.VersionID}
+				}
+				result.Deleted = append(result.Deleted, deletedObject)
+			}
+			continue
+		case drivers.ObjectNameInvalid:
+			deleteError = getErrorCode(NoSuchKey)
+		case drivers.APINotImplemented:
+			deleteError = getErrorCode(NotImplemented)
+		default:
+			if err == errPolicyDenied {
+				deleteError = getErrorCode(AccessDenied)
+			} else {
+				log.Error.Println(iodine.New(err, nil))
+				deleteError = getErrorCode(InternalError)
+			}
+		}
+		result.Error = append(result.Error, DeleteError{
+			Key:       object.Key,
+			VersionID: object.VersionID,
+			Code:      deleteError.Code,
+			Message:   deleteError.Description,
+		})
+	}
+	return result
+}
+
 // generateListPartsResult
 func generateListPartsResult(objectMetadata drivers.ObjectResourcesMetadata) ListPartsResponse {
-	// TODO - support EncodingType in xml decoding
 	listPartsResponse := ListPartsResponse{}
 	listPartsResponse.Bucket = objectMetadata.Bucket
-	listPartsResponse.Key = objectMetadata.Key
+	listPartsResponse.Key = encodeName(objectMetadata.Key, objectMetadata.EncodingType)
+	listPartsResponse.EncodingType = objectMetadata.EncodingType
 	listPartsResponse.UploadID = objectMetadata.UploadID
 	listPartsResponse.StorageClass = "STANDARD"
 	listPartsResponse.Initiator.ID = "minio"
@@ -162,14 +335,15 @@ func generateListPartsResult(objectMetadata drivers.ObjectResourcesMetadata) Lis
 
 // generateListMultipartUploadsResult
 func generateListMultipartUploadsResult(bucket string, metadata drivers.BucketMultipartResourcesMetadata) ListMultipartUploadsResponse {
+	encodingType := metadata.EncodingType
 	listMultipartUploadsResponse := ListMultipartUploadsResponse{}
 	listMultipartUploadsResponse.Bucket = bucket
-	listMultipartUploadsResponse.Delimiter = metadata.Delimiter
+	listMultipartUploadsResponse.Delimiter = encodeName(metadata.Delimiter, encodingType)
 	listMultipartUploadsResponse.IsTruncated = metadata.IsTruncated
-	listMultipartUploadsResponse.EncodingType = metadata.EncodingType
-	listMultipartUploadsResponse.Prefix = metadata.Prefix
-	listMultipartUploadsResponse.KeyMarker = metadata.KeyMarker
-	listMultipartUploadsResponse.NextKeyMarker = metadata.NextKeyMarker
+	listMultipartUploadsResponse.EncodingType = encodingType
+	listMultipartUploadsResponse.Prefix = encodeName(metadata.Prefix, encodingType)
+	listMultipartUploadsResponse.KeyMarker = encodeName(metadata.KeyMarker, encodingType)
+	listMultipartUploadsResponse.NextKeyMarker = encodeName(metadata.NextKeyMarker, encodingType)
 	listMultipartUploadsResponse.MaxUploads = metadata.MaxUploads
 	listMultipartUploadsResponse.NextUploadIDMarker = metadata.NextUploadIDMarker
 	listMultipartUploadsResponse.UploadIDMarker = metadata.UploadIDMarker
@@ -178,10 +352,13 @@ func generateListMultipartUploadsResult(bucket string, metadata drivers.BucketMu
 	for _, upload := range metadata.Upload {
 		newUpload := &Upload{}
 		newUpload.UploadID = upload.UploadID
-		newUpload.Key = upload.Key
+		newUpload.Key = encodeName(upload.Key, encodingType)
 		newUpload.Initiated = upload.Initiated.Format(iso8601Format)
 		listMultipartUploadsResponse.Upload = append(listMultipartUploadsResponse.Upload, newUpload)
 	}
+	for _, prefix := range metadata.CommonPrefixes {
+		listMultipartUploadsResponse.CommonPrefixes = append(listMultipartUploadsResponse.CommonPrefixes, &CommonPrefix{Prefix: encodeName(prefix, encodingType)})
+	}
 	return listMultipartUploadsResponse
 }
 
@@ -194,6 +371,10 @@ func writeSuccessResponse(w http.ResponseWriter, acceptsContentType contentType)
 // writeErrorRespone write error headers
 func writeErrorResponse(w http.ResponseWriter, req *http.Request, errorType int, acceptsContentType contentType, resource string) {
 	error := getErrorCode(errorType)
+	// keep the error code for the access log
+	if recorder, ok := w.(*accessLogWriter); ok {
+		recorder.setErrorCode(error.Code)
+	}
 	// generate error response
 	errorResponse := getErrorResponse(error, resource)
 	encodedErrorResponse := encodeErrorResponse(errorResponse, acceptsContentType)
@@ -204,3 +385,42 @@ func writeErrorResponse(w http.ResponseWriter, req *http.Request, errorType int,
 	// write error body
 	w.Write(encodedErrorResponse)
 }
+
+// writePostPolicyResponse - redirect to success_action_redirect when given, otherwise reply with success_action_status
+func writePostPolicyResponse(w http.ResponseWriter, req *http.Request, form postPolicyForm, bucket, key, etag string, acceptsContentType contentType) {
+	location := "/" + bucket + "/" + key
+	w.Header().Set("Location", location)
+
+	redirect := form["success_action_redirect"]
+	if redirect == "" {
+		redirect = form["redirect"]
+	}
+	if redirectURL, err := url.Parse(redirect); redirect != "" && err == nil {
+		query := redirectURL.Query()
+		query.Set("bucket", bucket)
+		query.Set("key", key)
+		query.Set("etag", "\""+etag+"\"")
+		redirectURL.RawQuery = query.Encode()
+		http.Redirect(w, req, redirectURL.String(), http.StatusSeeOther)
+		return
+	}
+
+	switch form["success_action_status"] {
+	case "200":
+		writeSuccessResponse(w, acceptsContentType)
+	case "201":
+		response := PostResponse{
+			Location: location,
+			Bucket:   bucket,
+			Key:      key,
+			ETag:     "\"" + etag + "\"",
+		}
+		encodedSuccessResponse := encodeSuccessResponse(response, acceptsContentType)
+		setCommonHeaders(w, getContentTypeString(acceptsContentType), len(encodedSuccessResponse))
+		w.WriteHeader(http.StatusCreated)
+		w.Write(encodedSuccessResponse)
+	default:
+		setCommonHeaders(w, getContentTypeString(acceptsContentType), 0)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -18,21 +18,29 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	router "github.com/gorilla/mux"
 	"github.com/minio/minio/pkg/api/logging"
 	"github.com/minio/minio/pkg/api/quota"
+	"github.com/minio/minio/pkg/iodine"
 	"github.com/minio/minio/pkg/storage/drivers"
 )
 
 type minioAPI struct {
-	driver drivers.Driver
+	driver   drivers.Driver
+	notifier *eventNotifier
+	users    *userStore
 }
 
 // Config api configurable parameters
 type Config struct {
 	RateLimit int
+	Domain    string
 	driver    drivers.Driver
+	notifier  *eventNotifier
+	logger    *accessLogger
+	users     *userStore
 }
 
 // GetDriver - get a an existing set driver
@@ -45,17 +53,67 @@ func (c *Config) SetDriver(driver drivers.Driver) {
 	c.driver = driver
 }
 
+// LoadUsers - read the configured users whose access keys sign requests, they are kept in memory
+func (c *Config) LoadUsers() error {
+	users := newUserStore()
+	if err := users.reload(); err != nil {
+		return iodine.New(err, nil)
+	}
+	c.users = users
+	return nil
+}
+
+// SetNotificationQueue - deliver bucket event notifications to webhooks, events waiting for delivery
+// are queued under path
+func (c *Config) SetNotificationQueue(path string) error {
+	notifier, err := newEventNotifier(path)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	notifier.start()
+	c.notifier = notifier
+	return nil
+}
+
+// Stop - stop delivering bucket event notifications and writing access logs, events waiting for delivery
+// stay queued for the next start while pending access log records are written right away
+func (c *Config) Stop() {
+	if c.notifier != nil {
+		c.notifier.stop()
+	}
+	if c.logger != nil {
+		c.logger.stop()
+	}
+}
+
+// SetAccessLogInterval - write the access log of buckets with logging enabled into their target
+// buckets every interval, through the driver set before
+func (c *Config) SetAccessLogInterval(interval time.Duration) {
+	logger := newAccessLogger(c.driver)
+	logger.start(interval)
+	c.logger = logger
+}
+
 // HTTPHandler - http wrapper handler
 func HTTPHandler(config Config) http.Handler {
 	var mux *router.Router
 	var api = minioAPI{}
 	api.driver = config.GetDriver()
+	api.notifier = config.notifier
+	// without users loaded up front they are read along with the first signed request
+	api.users = config.users
+	if api.users == nil {
+		api.users = newUserStore()
+	}
 
 	mux = router.NewRouter()
 	mux.HandleFunc("/", api.listBucketsHandler).Methods("GET")
 	mux.HandleFunc("/{bucket}", api.listObjectsHandler).Methods("GET")
 	mux.HandleFunc("/{bucket}", api.putBucketHandler).Methods("PUT")
 	mux.HandleFunc("/{bucket}", api.headBucketHandler).Methods("HEAD")
+	mux.HandleFunc("/{bucket}", api.postPolicyHandler).Methods("POST")
+	mux.HandleFunc("/{bucket}", api.deleteBucketHandler).Methods("DELETE")
+	mux.HandleFunc("/{bucket}", api.preflightHandler).Methods("OPTIONS")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.headObjectHandler).Methods("HEAD")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.putObjectPartHandler).Queries("partNumber", "{partNumber:[0-9]+}", "uploadId", "{uploadId:.*}").Methods("PUT")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.listObjectPartsHandler).Queries("uploadId", "{uploadId:.*}").Methods("GET")
@@ -63,18 +121,22 @@ func HTTPHandler(config Config) http.Handler {
 	mux.HandleFunc("/{bucket}/{object:.*}", api.newMultipartUploadHandler).Methods("POST")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.abortMultipartUploadHandler).Queries("uploadId", "{uploadId:.*}").Methods("DELETE")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.getObjectHandler).Methods("GET")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.copyObjectHandler).Headers("X-Amz-Copy-Source", "").Methods("PUT")
 	mux.HandleFunc("/{bucket}/{object:.*}", api.putObjectHandler).Methods("PUT")
-
-	// not implemented yet
-	mux.HandleFunc("/{bucket}", api.deleteBucketHandler).Methods("DELETE")
-
-	// unsupported API
 	mux.HandleFunc("/{bucket}/{object:.*}", api.deleteObjectHandler).Methods("DELETE")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.preflightHandler).Methods("OPTIONS")
 
 	handler := validContentTypeHandler(mux)
 	handler = timeValidityHandler(handler)
 	handler = ignoreResourcesHandler(handler)
-	handler = validateAuthHeaderHandler(handler)
+	handler = validateAuthHeaderHandler(handler, api.users)
+	handler = corsResponseHandler(handler, api.driver)
+	if config.logger != nil {
+		handler = serverAccessLogHandler(handler, config.logger)
+	}
+	if config.Domain != "" {
+		handler = virtualHostStyleHandler(handler, config.Domain)
+	}
 	//	handler = quota.BandwidthCap(h, 25*1024*1024, time.Duration(30*time.Minute))
 	//	handler = quota.BandwidthCap(h, 100*1024*1024, time.Duration(24*time.Hour))
 	//	handler = quota.RequestLimit(h, 100, time.Duration(30*time.Minute))
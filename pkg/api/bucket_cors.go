@@ -0,0 +1,213 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio/pkg/iodine"
+	"github.com/minio/minio/pkg/storage/drivers"
+)
+
+// maximum size of a CORS configuration document
+const maxCORSConfigurationSize = 64 * 1024
+
+// maximum number of rules in a CORS configuration
+const maxCORSRules = 100
+
+// bucket resource name the CORS configuration is kept under
+const bucketCORSResource = "cors"
+
+var errMalformedCORSConfiguration = errors.New("Malformed CORS configuration")
+
+// methods a CORS rule may allow
+var corsAllowedMethods = map[string]bool{
+	"GET":    true,
+	"PUT":    true,
+	"POST":   true,
+	"DELETE": true,
+	"HEAD":   true,
+}
+
+// parseCORSConfiguration - decode and validate a CORS configuration document
+//
+//	<CORSConfiguration>
+//	  <CORSRule>
+//	    <AllowedOrigin>http://www.example.com</AllowedOrigin>
+//	    <AllowedMethod>PUT</AllowedMethod>
+//	    <AllowedHeader>*</AllowedHeader>
+//	    <MaxAgeSeconds>3000</MaxAgeSeconds>
+//	    <ExposeHeader>ETag</ExposeHeader>
+//	  </CORSRule>
+//	</CORSConfiguration>
+func parseCORSConfiguration(data []byte) (*CORSConfiguration, error) {
+	config := new(CORSConfiguration)
+	if err := xml.Unmarshal(data, config); err != nil {
+		return nil, errMalformedCORSConfiguration
+	}
+	if len(config.CORSRule) == 0 || len(config.CORSRule) > maxCORSRules {
+		return nil, errMalformedCORSConfiguration
+	}
+	for _, rule := range config.CORSRule {
+		if len(rule.AllowedOrigin) == 0 || len(rule.AllowedMethod) == 0 || rule.MaxAgeSeconds < 0 {
+			return nil, errMalformedCORSConfiguration
+		}
+		for _, method := range rule.AllowedMethod {
+			if !corsAllowedMethods[method] {
+				return nil, errMalformedCORSConfiguration
+			}
+		}
+		// origins and headers may carry at most one wildcard each
+		for _, origin := range rule.AllowedOrigin {
+			if strings.Count(origin, "*") > 1 {
+				return nil, errMalformedCORSConfiguration
+			}
+		}
+		for _, header := range rule.AllowedHeader {
+			if strings.Count(header, "*") > 1 {
+				return nil, errMalformedCORSConfiguration
+			}
+		}
+	}
+	return config, nil
+}
+
+// getMatchingRule - first rule allowing the origin, method and every one of the request headers
+func (c *CORSConfiguration) getMatchingRule(origin, method string, headers []string) *CORSRule {
+	for i := range c.CORSRule {
+		rule := &c.CORSRule[i]
+		if rule.isMatch(origin, method, headers) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// isMatch - origins are matched as is, header names case insensitive
+func (r *CORSRule) isMatch(origin, method string, headers []string) bool {
+	originMatch := false
+	for _, pattern := range r.AllowedOrigin {
+		if corsWildcardMatch(pattern, origin) {
+			originMatch = true
+			break
+		}
+	}
+	if !originMatch {
+		return false
+	}
+	methodMatch := false
+	for _, allowedMethod := range r.AllowedMethod {
+		if allowedMethod == method {
+			methodMatch = true
+			break
+		}
+	}
+	if !methodMatch {
+		return false
+	}
+	for _, header := range headers {
+		headerMatch := false
+		for _, pattern := range r.AllowedHeader {
+			if corsWildcardMatch(strings.ToLower(pattern), strings.ToLower(header)) {
+				headerMatch = true
+				break
+			}
+		}
+		if !headerMatch {
+			return false
+		}
+	}
+	return true
+}
+
+// corsWildcardMatch - '*' is the only wildcard of CORS rules, it matches any sequence
+func corsWildcardMatch(pattern, text string) bool {
+	i := strings.Index(pattern, "*")
+	if i == -1 {
+		return pattern == text
+	}
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	return len(text) >= len(prefix)+len(suffix) && strings.HasPrefix(text, prefix) && strings.HasSuffix(text, suffix)
+}
+
+// getCORSRequestHeaders - header names listed in Access-Control-Request-Headers of a preflight request
+func getCORSRequestHeaders(req *http.Request) []string {
+	var headers []string
+	for _, value := range req.Header[http.CanonicalHeaderKey("Access-Control-Request-Headers")] {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				headers = append(headers, header)
+			}
+		}
+	}
+	return headers
+}
+
+// setCORSHeaders - Access-Control-* response headers of the rule a request matched
+func setCORSHeaders(w http.ResponseWriter, rule *CORSRule, origin string) {
+	allowOrigin := origin
+	for _, pattern := range rule.AllowedOrigin {
+		if pattern == "*" {
+			allowOrigin = "*"
+			break
+		}
+	}
+	w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+	// credentials are never allowed together with a wildcard origin
+	if allowOrigin != "*" {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	w.Header().Set("Access-Control-Allow-Methods", strings.Join(rule.AllowedMethod, ", "))
+	if len(rule.ExposeHeader) > 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(rule.ExposeHeader, ", "))
+	}
+	if rule.MaxAgeSeconds > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(rule.MaxAgeSeconds))
+	}
+}
+
+// getBucketCORSConfiguration - stored CORS configuration of a bucket
+func getBucketCORSConfiguration(driver drivers.Driver, bucket string) (*CORSConfiguration, error) {
+	data, err := driver.GetBucketResource(bucket, bucketCORSResource)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	config, err := parseCORSConfiguration(data)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	return config, nil
+}
+
+// getRequestBucket - bucket a request is addressed to, first element of the request path
+func getRequestBucket(req *http.Request) string {
+	path := strings.TrimPrefix(req.URL.Path, "/")
+	return strings.SplitN(path, "/", 2)[0]
+}
+
+// getRequestObject - object a request is addressed to, rest of the request path after the bucket
+func getRequestObject(req *http.Request) string {
+	path := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)
+	if len(path) < 2 {
+		return ""
+	}
+	return path[1]
+}
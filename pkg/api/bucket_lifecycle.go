@@ -0,0 +1,276 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"time"
+
+	"github.com/minio/minio/pkg/iodine"
+	"github.com/minio/minio/pkg/storage/drivers"
+	"github.com/minio/minio/pkg/utils/log"
+)
+
+// maximum size of a lifecycle configuration document
+const maxLifecycleConfigurationSize = 64 * 1024
+
+// maximum number of rules in a lifecycle configuration
+const maxLifecycleRules = 1000
+
+// bucket resource name the lifecycle configuration is kept under
+const bucketLifecycleResource = "lifecycle"
+
+var errMalformedLifecycleConfiguration = errors.New("Malformed lifecycle configuration")
+
+// parseLifecycleConfiguration - decode and validate a lifecycle configuration document
+//
+//	<LifecycleConfiguration>
+//	  <Rule>
+//	    <ID>expire-logs</ID>
+//	    <Filter><Prefix>logs/</Prefix></Filter>
+//	    <Status>Enabled</Status>
+//	    <Expiration><Days>30</Days></Expiration>
+//	    <AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload>
+//	  </Rule>
+//	</LifecycleConfiguration>
+func parseLifecycleConfiguration(data []byte) (*LifecycleConfiguration, error) {
+	config := new(LifecycleConfiguration)
+	if err := xml.Unmarshal(data, config); err != nil {
+		return nil, errMalformedLifecycleConfiguration
+	}
+	if len(config.Rule) == 0 || len(config.Rule) > maxLifecycleRules {
+		return nil, errMalformedLifecycleConfiguration
+	}
+	ids := make(map[string]bool)
+	for _, rule := range config.Rule {
+		if len(rule.ID) > 255 || (rule.ID != "" && ids[rule.ID]) {
+			return nil, errMalformedLifecycleConfiguration
+		}
+		ids[rule.ID] = true
+		if rule.Status != "Enabled" && rule.Status != "Disabled" {
+			return nil, errMalformedLifecycleConfiguration
+		}
+		// the prefix is either given directly or through the filter, never both
+		if rule.Filter != nil && rule.Prefix != "" {
+			return nil, errMalformedLifecycleConfiguration
+		}
+		if rule.Expiration == nil && rule.AbortIncompleteMultipartUpload == nil {
+			return nil, errMalformedLifecycleConfiguration
+		}
+		if rule.Expiration != nil {
+			expiration := rule.Expiration
+			switch {
+			case expiration.Days > 0 && expiration.Date == "":
+			case expiration.Days == 0 && expiration.Date != "":
+				// expiration dates are always at midnight UTC
+				date, err := time.Parse(time.RFC3339, expiration.Date)
+				if err != nil || !date.Equal(date.UTC().Truncate(24*time.Hour)) {
+					return nil, errMalformedLifecycleConfiguration
+				}
+			default:
+				return nil, errMalformedLifecycleConfiguration
+			}
+		}
+		if rule.AbortIncompleteMultipartUpload != nil && rule.AbortIncompleteMultipartUpload.DaysAfterInitiation <= 0 {
+			return nil, errMalformedLifecycleConfiguration
+		}
+	}
+	return config, nil
+}
+
+// getPrefix - key prefix the rule applies to
+func (r LifecycleRule) getPrefix() string {
+	if r.Filter != nil {
+		return r.Filter.Prefix
+	}
+	return r.Prefix
+}
+
+// lifecycleDeadline - time a number of days after t, rounded up to the following midnight UTC
+func lifecycleDeadline(t time.Time, days int) time.Time {
+	deadline := t.UTC().Add(time.Duration(days) * 24 * time.Hour)
+	midnight := deadline.Truncate(24 * time.Hour)
+	if midnight.Before(deadline) {
+		midnight = midnight.Add(24 * time.Hour)
+	}
+	return midnight
+}
+
+// isObjectExpired - an object expires a number of days after its creation or on a fixed date
+func (r LifecycleRule) isObjectExpired(created, now time.Time) bool {
+	if r.Status != "Enabled" || r.Expiration == nil {
+		return false
+	}
+	if r.Expiration.Date != "" {
+		date, err := time.Parse(time.RFC3339, r.Expiration.Date)
+		return err == nil && !now.Before(date)
+	}
+	return !now.Before(lifecycleDeadline(created, r.Expiration.Days))
+}
+
+// isUploadStale - incomplete multipart uploads are aborted a number of days after initiation
+func (r LifecycleRule) isUploadStale(initiated, now time.Time) bool {
+	if r.Status != "Enabled" || r.AbortIncompleteMultipartUpload == nil {
+		return false
+	}
+	return !now.Before(lifecycleDeadline(initiated, r.AbortIncompleteMultipartUpload.DaysAfterInitiation))
+}
+
+// getBucketLifecycleConfiguration - stored lifecycle configuration of a bucket
+func getBucketLifecycleConfiguration(driver drivers.Driver, bucket string) (*LifecycleConfiguration, error) {
+	data, err := driver.GetBucketResource(bucket, bucketLifecycleResource)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	config, err := parseLifecycleConfiguration(data)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	return config, nil
+}
+
+// StartLifecycle - apply the lifecycle rules of all buckets once every interval in a go routine,
+// closing the returned channel stops it
+func StartLifecycle(driver drivers.Driver, interval time.Duration) chan<- struct{} {
+	stop := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := applyLifecycle(driver, time.Now().UTC()); err != nil {
+					log.Error.Println(iodine.New(err, nil))
+				}
+			case <-stop:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return stop
+}
+
+// applyLifecycle - remove expired objects and stale multipart uploads of every bucket with a lifecycle configuration
+func applyLifecycle(driver drivers.Driver, now time.Time) error {
+	buckets, err := driver.ListBuckets()
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	for _, bucket := range buckets {
+		config, err := getBucketLifecycleConfiguration(driver, bucket.Name)
+		switch iodine.ToError(err).(type) {
+		case nil:
+		case drivers.BucketResourceNotFound, drivers.BucketNotFound:
+			// no rules, or the bucket is gone since it was listed
+			continue
+		default:
+			// a broken bucket must not keep the others from being processed
+			log.Error.Println(iodine.New(err, map[string]string{"bucket": bucket.Name}))
+			continue
+		}
+		for _, rule := range config.Rule {
+			if rule.Status != "Enabled" {
+				continue
+			}
+			if err := expireObjects(driver, bucket.Name, rule, now); err != nil {
+				log.Error.Println(iodine.New(err, map[string]string{"bucket": bucket.Name}))
+			}
+			if err := abortStaleUploads(driver, bucket.Name, rule, now); err != nil {
+				log.Error.Println(iodine.New(err, map[string]string{"bucket": bucket.Name}))
+			}
+		}
+	}
+	return nil
+}
+
+// expireObjects - delete the objects under the prefix of the rule which have expired
+func expireObjects(driver drivers.Driver, bucket string, rule LifecycleRule, now time.Time) error {
+	if rule.Expiration == nil {
+		return nil
+	}
+	resources := drivers.BucketResourcesMetadata{
+		Prefix:  rule.getPrefix(),
+		Maxkeys: maxObjectList,
+	}
+	for {
+		objects, resultResources, err := driver.ListObjects(bucket, resources)
+		if err != nil {
+			return iodine.New(err, nil)
+		}
+		var keys []string
+		for _, object := range objects {
+			if rule.isObjectExpired(object.Created, now) {
+				keys = append(keys, object.Key)
+			}
+		}
+		if len(keys) > 0 {
+			errs, err := driver.DeleteObjects(bucket, keys)
+			if err != nil {
+				return iodine.New(err, nil)
+			}
+			for key, err := range errs {
+				log.Error.Println(iodine.New(err, map[string]string{"bucket": bucket, "key": key}))
+			}
+		}
+		if !resultResources.IsTruncated || len(objects) == 0 {
+			return nil
+		}
+		// without delimiter the listing continues after the last key
+		resources.Marker = objects[len(objects)-1].Key
+	}
+}
+
+// abortStaleUploads - abort the incomplete multipart uploads under the prefix of the rule
+func abortStaleUploads(driver drivers.Driver, bucket string, rule LifecycleRule, now time.Time) error {
+	if rule.AbortIncompleteMultipartUpload == nil {
+		return nil
+	}
+	resources := drivers.BucketMultipartResourcesMetadata{
+		Prefix:     rule.getPrefix(),
+		MaxUploads: maxObjectList,
+	}
+	for {
+		resultResources, err := driver.ListMultipartUploads(bucket, resources)
+		switch iodine.ToError(err).(type) {
+		case nil:
+		case drivers.APINotImplemented:
+			// driver without multipart support has no uploads to abort
+			return nil
+		default:
+			return iodine.New(err, nil)
+		}
+		for _, upload := range resultResources.Upload {
+			if !rule.isUploadStale(upload.Initiated, now) {
+				continue
+			}
+			err := driver.AbortMultipartUpload(bucket, upload.Key, upload.UploadID)
+			switch iodine.ToError(err).(type) {
+			case nil, drivers.InvalidUploadID:
+			default:
+				return iodine.New(err, nil)
+			}
+		}
+		// stop as well when the listing does not advance
+		if !resultResources.IsTruncated || (resultResources.NextKeyMarker == resources.KeyMarker &&
+			resultResources.NextUploadIDMarker == resources.UploadIDMarker) {
+			return nil
+		}
+		resources.KeyMarker = resultResources.NextKeyMarker
+		resources.UploadIDMarker = resultResources.NextUploadIDMarker
+	}
+}
@@ -0,0 +1,356 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/iodine"
+	"github.com/minio/minio/pkg/storage/drivers"
+	"github.com/minio/minio/pkg/utils/log"
+)
+
+// maximum size of a logging configuration document
+const maxLoggingConfigurationSize = 64 * 1024
+
+// bucket resource name the logging configuration is kept under
+const bucketLoggingResource = "logging"
+
+// number of access log records kept in memory before they are flushed ahead of time
+const maxPendingAccessLogRecords = 10000
+
+var errMalformedLoggingConfiguration = errors.New("Malformed logging configuration")
+
+// parseLoggingConfiguration - decode and validate a logging configuration document
+//
+//	<BucketLoggingStatus>
+//	  <LoggingEnabled>
+//	    <TargetBucket>logs</TargetBucket>
+//	    <TargetPrefix>photos/</TargetPrefix>
+//	  </LoggingEnabled>
+//	</BucketLoggingStatus>
+//
+// a status without LoggingEnabled turns off the access log of the bucket
+func parseLoggingConfiguration(data []byte) (*BucketLoggingStatus, error) {
+	config := new(BucketLoggingStatus)
+	if err := xml.Unmarshal(data, config); err != nil {
+		return nil, errMalformedLoggingConfiguration
+	}
+	if config.LoggingEnabled != nil && config.LoggingEnabled.TargetBucket == "" {
+		return nil, errMalformedLoggingConfiguration
+	}
+	return config, nil
+}
+
+// getBucketLoggingConfiguration - stored logging configuration of a bucket
+func getBucketLoggingConfiguration(driver drivers.Driver, bucket string) (*BucketLoggingStatus, error) {
+	data, err := driver.GetBucketResource(bucket, bucketLoggingResource)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	config, err := parseLoggingConfiguration(data)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	return config, nil
+}
+
+// accessLogTarget - bucket and key prefix access log objects are written to
+type accessLogTarget struct {
+	bucket string
+	prefix string
+}
+
+// accessLogger - keeps the access log records of buckets with logging enabled in memory, and
+// periodically writes them as objects into the target buckets
+type accessLogger struct {
+	driver drivers.Driver
+
+	lock    *sync.Mutex
+	pending map[accessLogTarget][]string
+	count   int
+
+	flushNow chan struct{}
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// newAccessLogger - an access logger writing through driver
+func newAccessLogger(driver drivers.Driver) *accessLogger {
+	return &accessLogger{
+		driver:   driver,
+		lock:     new(sync.Mutex),
+		pending:  make(map[accessLogTarget][]string),
+		flushNow: make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// start - flush the pending records every interval, or earlier when too many are pending
+func (l *accessLogger) start(interval time.Duration) {
+	go func() {
+		defer close(l.stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-l.flushNow:
+			case <-l.done:
+				l.flush(time.Now().UTC())
+				return
+			}
+			l.flush(time.Now().UTC())
+		}
+	}()
+}
+
+// stop - stop flushing periodically once the records pending so far are written
+func (l *accessLogger) stop() {
+	close(l.done)
+	<-l.stopped
+}
+
+// record - keep the access log record of a request when logging is enabled for its bucket
+func (l *accessLogger) record(req *http.Request, w *accessLogWriter, start time.Time) {
+	bucket := getRequestBucket(req)
+	if bucket == "" {
+		return
+	}
+	config, err := getBucketLoggingConfiguration(l.driver, bucket)
+	if err != nil || config.LoggingEnabled == nil {
+		return
+	}
+	target := accessLogTarget{bucket: config.LoggingEnabled.TargetBucket, prefix: config.LoggingEnabled.TargetPrefix}
+	line := formatAccessLogRecord(req, w, bucket, start, time.Now().UTC())
+
+	l.lock.Lock()
+	l.pending[target] = append(l.pending[target], line)
+	l.count++
+	full := l.count >= maxPendingAccessLogRecords
+	l.lock.Unlock()
+	if full {
+		select {
+		case l.flushNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// flush - write the pending records of every target bucket as a new log object, records which can
+// not be written are dropped
+func (l *accessLogger) flush(now time.Time) {
+	l.lock.Lock()
+	pending := l.pending
+	l.pending = make(map[accessLogTarget][]string)
+	l.count = 0
+	l.lock.Unlock()
+
+	for target, lines := range pending {
+		key := target.prefix + now.Format("2006-01-02-15-04-05-") + getAccessLogID()
+		data := []byte(strings.Join(lines, ""))
+		_, err := l.driver.CreateObject(target.bucket, key, "text/plain", "", int64(len(data)), bytes.NewReader(data), drivers.ObjectAttributes{})
+		if err != nil {
+			log.Error.Println(iodine.New(err, map[string]string{"bucket": target.bucket, "key": key}))
+		}
+	}
+}
+
+// getAccessLogID - random upper case hex string naming log objects and identifying requests
+func getAccessLogID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return strings.ToUpper(hex.EncodeToString(id))
+}
+
+// accessLogWriter - captures the status, error code and size of a response for its access log record
+type accessLogWriter struct {
+	http.ResponseWriter
+	status    int
+	errorCode string
+	bytesSent int64
+}
+
+// WriteHeader - keep the status of the response
+func (w *accessLogWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write - count the bytes of the response body
+func (w *accessLogWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.bytesSent += int64(n)
+	return n, err
+}
+
+// setErrorCode - keep the code of an error response, see writeErrorResponse
+func (w *accessLogWriter) setErrorCode(code string) {
+	w.errorCode = code
+}
+
+// subresources naming the operation of a request in the access log, the first one present is used
+var accessLogOperations = []struct {
+	resource  string
+	operation string
+}{
+	{"partNumber", "PART"},
+	{"uploadId", "UPLOAD"},
+	{"uploads", "UPLOADS"},
+	{"delete", "MULTI_OBJECT_DELETE"},
+	{"acl", "ACL"},
+	{"policy", "BUCKETPOLICY"},
+	{"cors", "CORS"},
+	{"lifecycle", "LIFECYCLE"},
+	{"versioning", "VERSIONING"},
+	{"versions", "BUCKETVERSIONS"},
+	{"tagging", "TAGGING"},
+	{"website", "WEBSITE"},
+	{"notification", "NOTIFICATION"},
+	{"logging", "LOGGING_STATUS"},
+}
+
+// getAccessLogOperation - operation of a request in the form "REST.<method>.<resource>"
+func getAccessLogOperation(req *http.Request, object string) string {
+	query := req.URL.Query()
+	for _, operation := range accessLogOperations {
+		if _, ok := query[operation.resource]; ok {
+			return "REST." + req.Method + "." + operation.operation
+		}
+	}
+	switch {
+	case object != "" && req.Method == "PUT" && req.Header.Get("X-Amz-Copy-Source") != "":
+		return "REST.COPY.OBJECT"
+	case object != "":
+		return "REST." + req.Method + ".OBJECT"
+	default:
+		return "REST." + req.Method + ".BUCKET"
+	}
+}
+
+// names of the TLS versions in the access log
+var accessLogTLSVersions = map[uint16]string{
+	tls.VersionSSL30: "SSLv3",
+	tls.VersionTLS10: "TLSv1",
+	tls.VersionTLS11: "TLSv1.1",
+	tls.VersionTLS12: "TLSv1.2",
+}
+
+// formatAccessLogRecord - a line in the S3 server access log format
+//
+//	bucketowner bucket [time] remoteip requester requestid operation key "request-uri" status errorcode
+//	bytessent objectsize totaltime turnaroundtime "referrer" "useragent" versionid hostid sigversion
+//	ciphersuite authtype hostheader tlsversion
+//
+// fields without value, like the cipher suite which is not known to the handlers, are written as "-"
+func formatAccessLogRecord(req *http.Request, w *accessLogWriter, bucket string, start, end time.Time) string {
+	object := getRequestObject(req)
+	remoteIP, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		remoteIP = req.RemoteAddr
+	}
+	requestURI := req.RequestURI
+	if requestURI == "" {
+		requestURI = req.URL.RequestURI()
+	}
+	var objectSize int64
+	if object != "" {
+		switch req.Method {
+		case "PUT", "POST":
+			objectSize = req.ContentLength
+		case "GET", "HEAD":
+			objectSize, _ = strconv.ParseInt(w.Header().Get("Content-Length"), 10, 64)
+		}
+	}
+	signatureVersion, authType := "", ""
+	switch {
+	case strings.HasPrefix(req.Header.Get("Authorization"), authHeaderPrefixV2+" "):
+		signatureVersion, authType = "SigV2", "AuthHeader"
+	case req.Header.Get("Authorization") != "":
+		signatureVersion, authType = "SigV4", "AuthHeader"
+	case isPresignedRequest(req):
+		signatureVersion, authType = "SigV4", "QueryString"
+	}
+	var tlsVersion string
+	if req.TLS != nil {
+		tlsVersion = accessLogTLSVersions[req.TLS.Version]
+	}
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	fields := []string{
+		"-",
+		bucket,
+		"[" + start.Format("02/Jan/2006:15:04:05 -0700") + "]",
+		remoteIP,
+		getRequestAccessKey(req),
+		getAccessLogID(),
+		getAccessLogOperation(req, object),
+		url.QueryEscape(object),
+		strconv.Quote(req.Method + " " + requestURI + " " + req.Proto),
+		strconv.Itoa(status),
+		w.errorCode,
+		formatAccessLogSize(w.bytesSent),
+		formatAccessLogSize(objectSize),
+		strconv.FormatInt(int64(end.Sub(start)/time.Millisecond), 10),
+		"",
+		strconv.Quote(req.Referer()),
+		strconv.Quote(req.UserAgent()),
+		w.Header().Get("X-Amz-Version-Id"),
+		"",
+		signatureVersion,
+		"",
+		authType,
+		req.Host,
+		tlsVersion,
+	}
+	for i, field := range fields {
+		if field == "" || field == `""` {
+			fields[i] = "-"
+		}
+	}
+	return strings.Join(fields, " ") + "\n"
+}
+
+// formatAccessLogSize - a size in the access log, "-" when nothing was transferred
+func formatAccessLogSize(size int64) string {
+	if size <= 0 {
+		return "-"
+	}
+	return strconv.FormatInt(size, 10)
+}
@@ -0,0 +1,557 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio/pkg/iodine"
+	"github.com/minio/minio/pkg/storage/drivers"
+	"github.com/minio/minio/pkg/utils/log"
+)
+
+// maximum size of a notification configuration document
+const maxNotificationConfigurationSize = 64 * 1024
+
+// maximum number of webhooks of a bucket
+const maxWebhookConfigurations = 100
+
+// bucket resource name the notification configuration is kept under
+const bucketNotificationResource = "notification"
+
+// maximum number of events waiting to be delivered, further events are dropped
+const maxQueuedEvents = 10000
+
+// number of times the delivery of an event is attempted before it is dropped
+const maxDeliveryAttempts = 5
+
+// time to wait before the first retry of a failed delivery, doubled with every attempt
+const deliveryRetryInterval = time.Second
+
+// time allowed for a webhook to answer
+const deliveryTimeout = 10 * time.Second
+
+// names of the events a webhook can be configured for
+var notificationEvents = map[string]bool{
+	"s3:ObjectCreated:*":                       true,
+	"s3:ObjectCreated:Put":                     true,
+	"s3:ObjectCreated:Post":                    true,
+	"s3:ObjectCreated:Copy":                    true,
+	"s3:ObjectCreated:CompleteMultipartUpload": true,
+	"s3:ObjectRemoved:*":                       true,
+	"s3:ObjectRemoved:Delete":                  true,
+	"s3:ObjectRemoved:DeleteMarkerCreated":     true,
+}
+
+var errMalformedNotificationConfiguration = errors.New("Malformed notification configuration")
+
+// parseNotificationConfiguration - decode and validate a notification configuration document
+//
+//	<NotificationConfiguration>
+//	  <WebhookConfiguration>
+//	    <Id>thumbnails</Id>
+//	    <Endpoint>http://localhost:8080/events</Endpoint>
+//	    <Event>s3:ObjectCreated:*</Event>
+//	    <Filter>
+//	      <S3Key>
+//	        <FilterRule><Name>prefix</Name><Value>images/</Value></FilterRule>
+//	        <FilterRule><Name>suffix</Name><Value>.jpg</Value></FilterRule>
+//	      </S3Key>
+//	    </Filter>
+//	  </WebhookConfiguration>
+//	</NotificationConfiguration>
+//
+// an empty configuration turns off the notifications of the bucket
+func parseNotificationConfiguration(data []byte) (*NotificationConfiguration, error) {
+	config := new(NotificationConfiguration)
+	if err := xml.Unmarshal(data, config); err != nil {
+		return nil, errMalformedNotificationConfiguration
+	}
+	if len(config.WebhookConfigurations) > maxWebhookConfigurations {
+		return nil, errMalformedNotificationConfiguration
+	}
+	ids := make(map[string]bool)
+	for _, webhook := range config.WebhookConfigurations {
+		if webhook.ID != "" {
+			if ids[webhook.ID] {
+				return nil, errMalformedNotificationConfiguration
+			}
+			ids[webhook.ID] = true
+		}
+		endpoint, err := url.Parse(webhook.Endpoint)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return nil, errMalformedNotificationConfiguration
+		}
+		if len(webhook.Events) == 0 {
+			return nil, errMalformedNotificationConfiguration
+		}
+		for _, event := range webhook.Events {
+			if !notificationEvents[event] {
+				return nil, errMalformedNotificationConfiguration
+			}
+		}
+		if webhook.Filter != nil {
+			names := make(map[string]bool)
+			for _, rule := range webhook.Filter.FilterRules {
+				if (rule.Name != "prefix" && rule.Name != "suffix") || names[rule.Name] {
+					return nil, errMalformedNotificationConfiguration
+				}
+				names[rule.Name] = true
+			}
+		}
+	}
+	return config, nil
+}
+
+// getBucketNotificationConfiguration - stored notification configuration of a bucket
+func getBucketNotificationConfiguration(driver drivers.Driver, bucket string) (*NotificationConfiguration, error) {
+	data, err := driver.GetBucketResource(bucket, bucketNotificationResource)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	config, err := parseNotificationConfiguration(data)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	return config, nil
+}
+
+// isEventMatch - whether a webhook is configured for an event on a key
+func (w WebhookConfiguration) isEventMatch(eventName, key string) bool {
+	matched := false
+	for _, event := range w.Events {
+		if event == eventName || (strings.HasSuffix(event, ":*") && strings.HasPrefix(eventName, strings.TrimSuffix(event, "*"))) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+	if w.Filter != nil {
+		for _, rule := range w.Filter.FilterRules {
+			switch rule.Name {
+			case "prefix":
+				if !strings.HasPrefix(key, rule.Value) {
+					return false
+				}
+			case "suffix":
+				if !strings.HasSuffix(key, rule.Value) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// eventRecords - body of the request delivering an event to a webhook
+type eventRecords struct {
+	Records []eventRecord `json:"Records"`
+}
+
+// eventRecord - an event in the format of S3 event notifications
+type eventRecord struct {
+	EventVersion      string            `json:"eventVersion"`
+	EventSource       string            `json:"eventSource"`
+	AwsRegion         string            `json:"awsRegion"`
+	EventTime         string            `json:"eventTime"`
+	EventName         string            `json:"eventName"`
+	UserIdentity      eventIdentity     `json:"userIdentity"`
+	RequestParameters map[string]string `json:"requestParameters"`
+	ResponseElements  map[string]string `json:"responseElements"`
+	S3                eventS3           `json:"s3"`
+}
+
+type eventIdentity struct {
+	PrincipalID string `json:"principalId"`
+}
+
+type eventS3 struct {
+	SchemaVersion   string      `json:"s3SchemaVersion"`
+	ConfigurationID string      `json:"configurationId"`
+	Bucket          eventBucket `json:"bucket"`
+	Object          eventObject `json:"object"`
+}
+
+type eventBucket struct {
+	Name          string        `json:"name"`
+	OwnerIdentity eventIdentity `json:"ownerIdentity"`
+	ARN           string        `json:"arn"`
+}
+
+type eventObject struct {
+	Key       string `json:"key"`
+	Size      int64  `json:"size,omitempty"`
+	ETag      string `json:"eTag,omitempty"`
+	VersionID string `json:"versionId,omitempty"`
+	Sequencer string `json:"sequencer"`
+}
+
+// newEventRecord - record of an event on an object, eventName is given without its "s3:" prefix
+func newEventRecord(req *http.Request, eventName, configurationID string, metadata drivers.ObjectMetadata) eventRecord {
+	now := time.Now().UTC()
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	versionID := metadata.VersionID
+	if versionID == "null" {
+		versionID = ""
+	}
+	return eventRecord{
+		EventVersion:      "2.0",
+		EventSource:       "aws:s3",
+		AwsRegion:         "us-east-1",
+		EventTime:         now.Format("2006-01-02T15:04:05.000Z"),
+		EventName:         eventName,
+		UserIdentity:      eventIdentity{PrincipalID: getRequestAccessKey(req)},
+		RequestParameters: map[string]string{"sourceIPAddress": host},
+		ResponseElements:  map[string]string{},
+		S3: eventS3{
+			SchemaVersion:   "1.0",
+			ConfigurationID: configurationID,
+			Bucket: eventBucket{
+				Name: metadata.Bucket,
+				ARN:  "arn:aws:s3:::" + metadata.Bucket,
+			},
+			Object: eventObject{
+				Key:       url.QueryEscape(metadata.Key),
+				Size:      metadata.Size,
+				ETag:      metadata.Md5,
+				VersionID: versionID,
+				Sequencer: fmt.Sprintf("%016X", now.UnixNano()),
+			},
+		},
+	}
+}
+
+// notifyEvent - queue an event on an object for every webhook of the bucket configured for it, the
+// metadata of created objects is looked up while removed objects only carry their key
+func (server *minioAPI) notifyEvent(req *http.Request, eventName, bucket, object string) {
+	if server.notifier == nil {
+		return
+	}
+	config, err := getBucketNotificationConfiguration(server.driver, bucket)
+	switch iodine.ToError(err).(type) {
+	case nil:
+	case drivers.BucketResourceNotFound:
+		return
+	default:
+		log.Error.Println(iodine.New(err, nil))
+		return
+	}
+	var webhooks []WebhookConfiguration
+	for _, webhook := range config.WebhookConfigurations {
+		if webhook.isEventMatch(eventName, object) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+	metadata := drivers.ObjectMetadata{Bucket: bucket, Key: object}
+	switch {
+	case strings.HasPrefix(eventName, "s3:ObjectCreated:"):
+		if metadata, err = server.driver.GetObjectMetadata(bucket, object); err != nil {
+			log.Error.Println(iodine.New(err, nil))
+			return
+		}
+	case eventName == "s3:ObjectRemoved:DeleteMarkerCreated":
+		marker, err := server.driver.GetObjectVersionMetadata(bucket, object, "")
+		if err != nil {
+			log.Error.Println(iodine.New(err, nil))
+			return
+		}
+		metadata.VersionID = marker.VersionID
+	}
+	for _, webhook := range webhooks {
+		record := newEventRecord(req, strings.TrimPrefix(eventName, "s3:"), webhook.ID, metadata)
+		if err := server.notifier.enqueue(webhook.Endpoint, eventRecords{Records: []eventRecord{record}}); err != nil {
+			log.Error.Println(iodine.New(err, nil))
+		}
+	}
+}
+
+// notifyRemoveEvent - queue the event of an object deleted without naming a version, which leaves a
+// delete marker behind in a bucket with versioning
+func (server *minioAPI) notifyRemoveEvent(req *http.Request, bucket, object string) {
+	if server.notifier == nil {
+		return
+	}
+	eventName := "s3:ObjectRemoved:Delete"
+	if status, err := server.driver.GetBucketVersioning(bucket); err == nil && status != "" {
+		eventName = "s3:ObjectRemoved:DeleteMarkerCreated"
+	}
+	server.notifyEvent(req, eventName, bucket, object)
+}
+
+// queuedEvent - an event waiting in the queue to be delivered to a webhook
+type queuedEvent struct {
+	Endpoint string       `json:"endpoint"`
+	Event    eventRecords `json:"event"`
+}
+
+// deliveryState - failed deliveries to an endpoint of the first of its queued events, kept in memory
+// only so every event gets all its attempts again after a restart
+type deliveryState struct {
+	attempts int
+	next     time.Time
+}
+
+// eventNotifier - delivers events to webhooks in the background, events are queued as files under
+// queuePath so pending deliveries survive a restart of the server
+type eventNotifier struct {
+	queuePath     string
+	retryInterval time.Duration
+	client        *http.Client
+
+	lock     *sync.Mutex
+	sequence uint64
+	queued   int
+
+	wakeup  chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+var errEventQueueFull = errors.New("Event queue is full, event dropped")
+
+// newEventNotifier - a notifier queueing events under queuePath, picking up events left queued there
+func newEventNotifier(queuePath string) (*eventNotifier, error) {
+	if err := os.MkdirAll(queuePath, 0700); err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	n := &eventNotifier{
+		queuePath:     queuePath,
+		retryInterval: deliveryRetryInterval,
+		client:        &http.Client{Timeout: deliveryTimeout},
+		lock:          new(sync.Mutex),
+		wakeup:        make(chan struct{}, 1),
+		done:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	// partially written events are left behind by a crash while queueing, they were never acknowledged
+	files, err := ioutil.ReadDir(queuePath)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".tmp") {
+			os.Remove(filepath.Join(queuePath, file.Name()))
+		}
+	}
+	names, err := n.listQueue()
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	n.queued = len(names)
+	if len(names) > 0 {
+		last, err := strconv.ParseUint(strings.TrimSuffix(names[len(names)-1], ".json"), 10, 64)
+		if err == nil {
+			n.sequence = last
+		}
+	}
+	return n, nil
+}
+
+// listQueue - names of the queued events in the order they were queued, events still being written
+// by enqueue are left out
+func (n *eventNotifier) listQueue() ([]string, error) {
+	files, err := ioutil.ReadDir(n.queuePath)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	var names []string
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".json") {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// enqueue - persist an event for delivery to endpoint
+func (n *eventNotifier) enqueue(endpoint string, event eventRecords) error {
+	data, err := json.Marshal(queuedEvent{Endpoint: endpoint, Event: event})
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	n.lock.Lock()
+	if n.queued >= maxQueuedEvents {
+		n.lock.Unlock()
+		return iodine.New(errEventQueueFull, map[string]string{"endpoint": endpoint})
+	}
+	n.sequence++
+	name := fmt.Sprintf("%020d.json", n.sequence)
+	n.queued++
+	n.lock.Unlock()
+
+	// written aside and renamed so the dispatcher never reads a partial event
+	path := filepath.Join(n.queuePath, name)
+	if err := ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		n.dequeue("")
+		return iodine.New(err, nil)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		n.dequeue(path + ".tmp")
+		return iodine.New(err, nil)
+	}
+	select {
+	case n.wakeup <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// dequeue - remove a queued event
+func (n *eventNotifier) dequeue(path string) {
+	if path != "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Error.Println(iodine.New(err, nil))
+		}
+	}
+	n.lock.Lock()
+	n.queued--
+	n.lock.Unlock()
+}
+
+// start - deliver queued events until stop is called
+func (n *eventNotifier) start() {
+	go func() {
+		defer close(n.stopped)
+		states := make(map[string]*deliveryState)
+		for {
+			wait := n.deliverQueued(states, time.Now())
+			timer := time.NewTimer(wait)
+			select {
+			case <-n.wakeup:
+			case <-timer.C:
+			case <-n.done:
+				timer.Stop()
+				return
+			}
+			timer.Stop()
+		}
+	}()
+}
+
+// stop - stop delivering events once the delivery in progress is done, queued events are kept for
+// the next start
+func (n *eventNotifier) stop() {
+	close(n.done)
+	<-n.stopped
+}
+
+// deliverQueued - attempt the delivery of the queued events of every endpoint not waiting for a retry,
+// and return the time until the next retry is due. Events are delivered to an endpoint in the order they
+// were queued, a failed delivery holds back the later events of its own endpoint only
+func (n *eventNotifier) deliverQueued(states map[string]*deliveryState, now time.Time) time.Duration {
+	wait := time.Hour
+	names, err := n.listQueue()
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		return n.retryInterval
+	}
+	for _, name := range names {
+		path := filepath.Join(n.queuePath, name)
+		event, err := n.readQueued(path)
+		if err != nil {
+			// an event which can not be read is never delivered
+			log.Error.Println(iodine.New(err, map[string]string{"event": name}))
+			n.dequeue(path)
+			continue
+		}
+		state, ok := states[event.Endpoint]
+		if ok && now.Before(state.next) {
+			if state.next.Sub(now) < wait {
+				wait = state.next.Sub(now)
+			}
+			continue
+		}
+		err = n.deliver(event)
+		if err == nil {
+			delete(states, event.Endpoint)
+			n.dequeue(path)
+			continue
+		}
+		if !ok {
+			state = new(deliveryState)
+			states[event.Endpoint] = state
+		}
+		state.attempts++
+		retry := n.retryInterval << uint(state.attempts-1)
+		if state.attempts >= maxDeliveryAttempts {
+			log.Error.Println(iodine.New(err, map[string]string{"event": name, "attempts": strconv.Itoa(state.attempts)}))
+			n.dequeue(path)
+			// the next event of the endpoint gets all its attempts, after the first retry interval
+			state.attempts = 0
+			retry = n.retryInterval
+		}
+		state.next = now.Add(retry)
+		if retry < wait {
+			wait = retry
+		}
+	}
+	return wait
+}
+
+// readQueued - read a queued event
+func (n *eventNotifier) readQueued(path string) (queuedEvent, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return queuedEvent{}, iodine.New(err, nil)
+	}
+	var event queuedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return queuedEvent{}, iodine.New(err, nil)
+	}
+	return event, nil
+}
+
+// deliver - POST a queued event to its webhook, any answer other than 2xx is a failed delivery
+func (n *eventNotifier) deliver(event queuedEvent) error {
+	body, err := json.Marshal(event.Event)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	response, err := n.client.Post(event.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return iodine.New(err, map[string]string{"endpoint": event.Endpoint})
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return iodine.New(errors.New(response.Status), map[string]string{"endpoint": event.Endpoint})
+	}
+	return nil
+}
@@ -0,0 +1,584 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio/pkg/iodine"
+	"github.com/minio/minio/pkg/storage/drivers"
+)
+
+// maximum size of a bucket policy document
+const maxBucketPolicySize = 20 * 1024
+
+// bucket resource name the policy document is kept under
+const bucketPolicyResource = "policy"
+
+// resource ARNs of a policy start with this prefix followed by bucket and object name
+const bucketPolicyARNPrefix = "arn:aws:s3:::"
+
+// bucket policy errors
+var (
+	errMalformedPolicy = errors.New("Malformed bucket policy")
+	errPolicyDenied    = errors.New("Access denied by bucket policy")
+)
+
+// policyManagementActions - actions configured users are never denied by the bucket policy, a policy
+// denying them to everyone would otherwise lock the owner out of the bucket for good
+var policyManagementActions = map[string]bool{
+	"s3:GetBucketPolicy":    true,
+	"s3:PutBucketPolicy":    true,
+	"s3:DeleteBucketPolicy": true,
+}
+
+// result of evaluating a bucket policy for a request
+type policyDecision int
+
+const (
+	policyNoMatch policyDecision = iota
+	policyAllow
+	policyDeny
+)
+
+// policyStringSet - policy elements which are either a single value or a list of values
+type policyStringSet []string
+
+// UnmarshalJSON - accept a string, number or boolean as well as a list of them
+func (s *policyStringSet) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+	*s = nil
+	for _, v := range values {
+		switch v.(type) {
+		case string, bool, float64:
+			*s = append(*s, fmt.Sprint(v))
+		default:
+			return errMalformedPolicy
+		}
+	}
+	return nil
+}
+
+// policyPrincipal - "*" or {"AWS": ["*" | "<accesskey>" | "arn:aws:iam::<account>:user/<accesskey>"]}
+type policyPrincipal struct {
+	AWS policyStringSet
+}
+
+// UnmarshalJSON - "*" is short for {"AWS": "*"}
+func (p *policyPrincipal) UnmarshalJSON(data []byte) error {
+	var wildcard string
+	if err := json.Unmarshal(data, &wildcard); err == nil {
+		if wildcard != "*" {
+			return errMalformedPolicy
+		}
+		p.AWS = policyStringSet{"*"}
+		return nil
+	}
+	var principal struct {
+		AWS policyStringSet
+	}
+	if err := json.Unmarshal(data, &principal); err != nil {
+		return err
+	}
+	p.AWS = principal.AWS
+	return nil
+}
+
+// policyStatement - single statement of a bucket policy
+type policyStatement struct {
+	Sid       string
+	Effect    string
+	Principal *policyPrincipal
+	Action    policyStringSet
+	Resource  policyStringSet
+	Condition map[string]map[string]policyStringSet
+}
+
+// bucketPolicy - IAM style access policy of a bucket
+//
+//	{
+//	  "Version": "2012-10-17",
+//	  "Statement": [{
+//	    "Effect": "Allow",
+//	    "Principal": "*",
+//	    "Action": ["s3:GetObject"],
+//	    "Resource": ["arn:aws:s3:::bucket/public/*"],
+//	    "Condition": {"IpAddress": {"aws:SourceIp": "192.168.1.0/24"}}
+//	  }]
+//	}
+type bucketPolicy struct {
+	Version   string
+	ID        string `json:"Id"`
+	Statement []policyStatement
+}
+
+// supported condition operators
+var policyConditionOperators = map[string]bool{
+	"StringEquals":              true,
+	"StringNotEquals":           true,
+	"StringEqualsIgnoreCase":    true,
+	"StringNotEqualsIgnoreCase": true,
+	"StringLike":                true,
+	"StringNotLike":             true,
+	"IpAddress":                 true,
+	"NotIpAddress":              true,
+	"Bool":                      true,
+}
+
+// parseBucketPolicy - decode the policy document and validate it for the bucket
+func parseBucketPolicy(data []byte, bucket string) (*bucketPolicy, error) {
+	policy := new(bucketPolicy)
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, errMalformedPolicy
+	}
+	switch policy.Version {
+	case "", "2008-10-17", "2012-10-17":
+	default:
+		return nil, errMalformedPolicy
+	}
+	if len(policy.Statement) == 0 {
+		return nil, errMalformedPolicy
+	}
+	bucketARN := bucketPolicyARNPrefix + bucket
+	for _, statement := range policy.Statement {
+		if statement.Effect != "Allow" && statement.Effect != "Deny" {
+			return nil, errMalformedPolicy
+		}
+		if statement.Principal == nil || len(statement.Principal.AWS) == 0 {
+			return nil, errMalformedPolicy
+		}
+		if len(statement.Action) == 0 || len(statement.Resource) == 0 {
+			return nil, errMalformedPolicy
+		}
+		for _, action := range statement.Action {
+			if action != "*" && !strings.HasPrefix(strings.ToLower(action), "s3:") {
+				return nil, errMalformedPolicy
+			}
+		}
+		// a policy may only refer to its own bucket and the objects in it
+		for _, resource := range statement.Resource {
+			if resource != bucketARN && !strings.HasPrefix(resource, bucketARN+"/") {
+				return nil, errMalformedPolicy
+			}
+		}
+		for operator, conditions := range statement.Condition {
+			if !policyConditionOperators[operator] {
+				return nil, errMalformedPolicy
+			}
+			if operator != "IpAddress" && operator != "NotIpAddress" {
+				continue
+			}
+			for _, values := range conditions {
+				for _, value := range values {
+					if parsePolicyCIDR(value) == nil {
+						return nil, errMalformedPolicy
+					}
+				}
+			}
+		}
+	}
+	return policy, nil
+}
+
+// parsePolicyCIDR - network of an "aws:SourceIp" condition, a bare address is a single host
+func parsePolicyCIDR(value string) *net.IPNet {
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil
+		}
+		if ip.To4() != nil {
+			value = value + "/32"
+		} else {
+			value = value + "/128"
+		}
+	}
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil
+	}
+	return network
+}
+
+// evaluate - an explicit deny of any matching statement wins over every allow
+func (p *bucketPolicy) evaluate(action, resource, accessKey string, conditionValues map[string]string) policyDecision {
+	decision := policyNoMatch
+	for _, statement := range p.Statement {
+		if !statement.isMatch(action, resource, accessKey, conditionValues) {
+			continue
+		}
+		if statement.Effect == "Deny" {
+			return policyDeny
+		}
+		decision = policyAllow
+	}
+	return decision
+}
+
+// isMatch - statement applies when principal, action, resource and every condition match
+func (s policyStatement) isMatch(action, resource, accessKey string, conditionValues map[string]string) bool {
+	if !s.isPrincipalMatch(accessKey) {
+		return false
+	}
+	actionMatch := false
+	for _, pattern := range s.Action {
+		// action names are case insensitive
+		if wildcardMatch(strings.ToLower(pattern), strings.ToLower(action)) {
+			actionMatch = true
+			break
+		}
+	}
+	if !actionMatch {
+		return false
+	}
+	resourceMatch := false
+	for _, pattern := range s.Resource {
+		if wildcardMatch(pattern, resource) {
+			resourceMatch = true
+			break
+		}
+	}
+	if !resourceMatch {
+		return false
+	}
+	for operator, conditions := range s.Condition {
+		for key, values := range conditions {
+			// condition keys are case insensitive
+			value, ok := conditionValues[strings.ToLower(key)]
+			if !isConditionMatch(operator, values, value, ok) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isPrincipalMatch - "*" matches everyone including anonymous requests, anything else only the named access key
+func (s policyStatement) isPrincipalMatch(accessKey string) bool {
+	for _, principal := range s.Principal.AWS {
+		if principal == "*" {
+			return true
+		}
+		if accessKey != "" && (principal == accessKey || strings.HasSuffix(principal, ":user/"+accessKey)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isConditionMatch - a condition matches when any of its values matches, negated operators when none does
+func isConditionMatch(operator string, values policyStringSet, value string, ok bool) bool {
+	anyMatch := func(match func(string) bool) bool {
+		if !ok {
+			return false
+		}
+		for _, v := range values {
+			if match(v) {
+				return true
+			}
+		}
+		return false
+	}
+	switch operator {
+	case "StringEquals":
+		return anyMatch(func(v string) bool { return v == value })
+	case "StringNotEquals":
+		return !anyMatch(func(v string) bool { return v == value })
+	case "StringEqualsIgnoreCase":
+		return anyMatch(func(v string) bool { return strings.EqualFold(v, value) })
+	case "StringNotEqualsIgnoreCase":
+		return !anyMatch(func(v string) bool { return strings.EqualFold(v, value) })
+	case "StringLike":
+		return anyMatch(func(v string) bool { return wildcardMatch(v, value) })
+	case "StringNotLike":
+		return !anyMatch(func(v string) bool { return wildcardMatch(v, value) })
+	case "IpAddress", "NotIpAddress":
+		ip := net.ParseIP(value)
+		match := anyMatch(func(v string) bool {
+			network := parsePolicyCIDR(v)
+			return ip != nil && network != nil && network.Contains(ip)
+		})
+		if operator == "NotIpAddress" {
+			return !match
+		}
+		return match
+	case "Bool":
+		return anyMatch(func(v string) bool { return strings.EqualFold(v, value) })
+	}
+	return false
+}
+
+// wildcardMatch - match text against a pattern where '*' matches any sequence and '?' any single character
+func wildcardMatch(pattern, text string) bool {
+	p, t := 0, 0
+	star, starText := -1, 0
+	for t < len(text) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == text[t]):
+			p++
+			t++
+		case p < len(pattern) && pattern[p] == '*':
+			star, starText = p, t
+			p++
+		case star != -1:
+			// let the last '*' swallow one more character and retry
+			starText++
+			p, t = star+1, starText
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// getPolicyAction - s3 action a request performs, empty when it can only be decided per object by the handler
+func getPolicyAction(req *http.Request, object string) string {
+	query := req.URL.Query()
+	if object == "" {
+		switch {
+		case isRequestBucketPolicy(query):
+			switch req.Method {
+			case "GET":
+				return "s3:GetBucketPolicy"
+			case "PUT":
+				return "s3:PutBucketPolicy"
+			case "DELETE":
+				return "s3:DeleteBucketPolicy"
+			}
+			return ""
+		case isRequestBucketCORS(query):
+			switch req.Method {
+			case "GET":
+				return "s3:GetBucketCORS"
+			case "PUT", "DELETE":
+				return "s3:PutBucketCORS"
+			}
+			return ""
+		case isRequestBucketLifecycle(query):
+			switch req.Method {
+			case "GET":
+				return "s3:GetLifecycleConfiguration"
+			case "PUT", "DELETE":
+				return "s3:PutLifecycleConfiguration"
+			}
+			return ""
+		case isRequestBucketVersioning(query):
+			switch req.Method {
+			case "GET":
+				return "s3:GetBucketVersioning"
+			case "PUT":
+				return "s3:PutBucketVersioning"
+			}
+			return ""
+		case isRequestBucketLogging(query):
+			switch req.Method {
+			case "GET":
+				return "s3:GetBucketLogging"
+			case "PUT":
+				return "s3:PutBucketLogging"
+			}
+			return ""
+		case isRequestBucketNotification(query):
+			switch req.Method {
+			case "GET":
+				return "s3:GetBucketNotification"
+			case "PUT":
+				return "s3:PutBucketNotification"
+			}
+			return ""
+		case isRequestBucketWebsite(query):
+			switch req.Method {
+			case "GET":
+				return "s3:GetBucketWebsite"
+			case "PUT":
+				return "s3:PutBucketWebsite"
+			case "DELETE":
+				return "s3:DeleteBucketWebsite"
+			}
+			return ""
+		case isRequestTagging(query):
+			switch req.Method {
+			case "GET":
+				return "s3:GetBucketTagging"
+			case "PUT", "DELETE":
+				return "s3:PutBucketTagging"
+			}
+			return ""
+		case isRequestACL(query):
+			switch req.Method {
+			case "GET":
+				return "s3:GetBucketAcl"
+			case "PUT":
+				return "s3:PutBucketAcl"
+			}
+			return ""
+		case isRequestBucketVersions(query):
+			return "s3:ListBucketVersions"
+		case isRequestUploads(query):
+			return "s3:ListBucketMultipartUploads"
+		}
+		switch req.Method {
+		case "GET", "HEAD":
+			return "s3:ListBucket"
+		case "DELETE":
+			return "s3:DeleteBucket"
+		}
+		return ""
+	}
+	_, isUpload := query["uploadId"]
+	_, isVersion := query["versionId"]
+	if isRequestACL(query) {
+		switch req.Method {
+		case "GET":
+			if isVersion {
+				return "s3:GetObjectVersionAcl"
+			}
+			return "s3:GetObjectAcl"
+		case "PUT":
+			return "s3:PutObjectAcl"
+		}
+		return ""
+	}
+	if isRequestTagging(query) {
+		switch req.Method {
+		case "GET":
+			if isVersion {
+				return "s3:GetObjectVersionTagging"
+			}
+			return "s3:GetObjectTagging"
+		case "PUT":
+			return "s3:PutObjectTagging"
+		case "DELETE":
+			return "s3:DeleteObjectTagging"
+		}
+		return ""
+	}
+	switch req.Method {
+	case "GET", "HEAD":
+		if isUpload {
+			return "s3:ListMultipartUploadParts"
+		}
+		if isVersion {
+			return "s3:GetObjectVersion"
+		}
+		return "s3:GetObject"
+	case "PUT", "POST":
+		return "s3:PutObject"
+	case "DELETE":
+		if isUpload {
+			return "s3:AbortMultipartUpload"
+		}
+		if isVersion {
+			return "s3:DeleteObjectVersion"
+		}
+		return "s3:DeleteObject"
+	}
+	return ""
+}
+
+// getPolicyResource - ARN of the bucket or object
+func getPolicyResource(bucket, object string) string {
+	if object == "" {
+		return bucketPolicyARNPrefix + bucket
+	}
+	return bucketPolicyARNPrefix + bucket + "/" + object
+}
+
+// getPolicyConditionValues - values of the supported condition keys for a request, keyed in lower case
+func getPolicyConditionValues(req *http.Request) map[string]string {
+	sourceIP, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		sourceIP = req.RemoteAddr
+	}
+	secureTransport := "false"
+	if req.TLS != nil {
+		secureTransport = "true"
+	}
+	values := map[string]string{
+		"aws:sourceip":        sourceIP,
+		"aws:securetransport": secureTransport,
+	}
+	if referer := req.Referer(); referer != "" {
+		values["aws:referer"] = referer
+	}
+	if userAgent := req.UserAgent(); userAgent != "" {
+		values["aws:useragent"] = userAgent
+	}
+	query := req.URL.Query()
+	for _, key := range []string{"prefix", "delimiter", "max-keys"} {
+		if value, ok := query[key]; ok {
+			values["s3:"+key] = value[0]
+		}
+	}
+	if acl := req.Header.Get("x-amz-acl"); acl != "" {
+		values["s3:x-amz-acl"] = acl
+	}
+	return values
+}
+
+// getRequestAccessKey - access key a request was signed with, through its auth header or presigned query
+//
+// signatures are verified before requests reach the handlers, a key returned here is authenticated
+func getRequestAccessKey(req *http.Request) string {
+	var a *auth
+	var err error
+	switch {
+	case req.Header.Get("Authorization") != "":
+		a, err = stripAuth(req)
+	case isPresignedRequest(req):
+		a, err = stripPresignedAuth(req)
+	default:
+		return ""
+	}
+	if err != nil {
+		return ""
+	}
+	return a.accessKey
+}
+
+// getBucketPolicyDecision - evaluate the bucket policy for an action on the bucket or one of its objects,
+// a bucket without policy never matches
+func (server *minioAPI) getBucketPolicyDecision(req *http.Request, bucket, object, action, accessKey string) (policyDecision, error) {
+	data, err := server.driver.GetBucketResource(bucket, bucketPolicyResource)
+	switch iodine.ToError(err).(type) {
+	case nil:
+	case drivers.BucketResourceNotFound:
+		return policyNoMatch, nil
+	default:
+		return policyNoMatch, iodine.New(err, nil)
+	}
+	policy, err := parseBucketPolicy(data, bucket)
+	if err != nil {
+		return policyNoMatch, iodine.New(err, nil)
+	}
+	return policy.evaluate(action, getPolicyResource(bucket, object), accessKey, getPolicyConditionValues(req)), nil
+}
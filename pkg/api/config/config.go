@@ -42,14 +42,24 @@ type User struct {
 	SecretKey string
 }
 
+// customConfigPath is used instead of ~/.minio when set
+var customConfigPath string
+
+// SetCustomConfigPath - set a custom config directory instead of ~/.minio
+func SetCustomConfigPath(path string) {
+	customConfigPath = path
+}
+
 // SetupConfig initialize config directory and template config
 func (c *Config) SetupConfig() error {
-	u, err := user.Current()
-	if err != nil {
-		return iodine.New(err, nil)
+	confPath := customConfigPath
+	if confPath == "" {
+		u, err := user.Current()
+		if err != nil {
+			return iodine.New(err, nil)
+		}
+		confPath = filepath.Join(u.HomeDir, ".minio")
 	}
-
-	confPath := filepath.Join(u.HomeDir, ".minio")
 	if err := os.MkdirAll(confPath, 0700); err != nil {
 		return iodine.New(err, nil)
 	}
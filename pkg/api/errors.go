@@ -65,11 +65,32 @@ const (
 	MethodNotAllowed
 	InvalidPart
 	InvalidPartOrder
+	BucketNotEmpty
+	AuthorizationHeaderMalformed
+	AuthorizationQueryParametersError
+	ExpiredPresignRequest
+	RequestNotReadyYet
+	MalformedPOSTRequest
+	InvalidPolicyDocument
+	PreconditionFailed
+	MalformedPolicy
+	NoSuchBucketPolicy
+	NoSuchCORSConfiguration
+	AccessForbidden
+	NoSuchLifecycleConfiguration
+	NoSuchVersion
+	InvalidTag
+	NoSuchTagSet
+	NoSuchWebsiteConfiguration
+	InvalidTargetBucketForLogging
+	InvalidArgument
+	MalformedACLError
+	XAmzContentSHA256Mismatch
 )
 
 // Error codes, non exhaustive list - standard HTTP errors
 const (
-	NotAcceptable = iota + 25
+	NotAcceptable = iota + 46
 )
 
 // Error code to Error structure map
@@ -110,7 +131,7 @@ var errorCodeResponse = map[int]Error{
 		HTTPStatusCode: http.StatusInternalServerError,
 	},
 	InvalidAccessKeyID: {
-		Code:           "InvalidAccessKeyID",
+		Code:           "InvalidAccessKeyId",
 		Description:    "The access key ID you provided does not exist in our records.",
 		HTTPStatusCode: http.StatusForbidden,
 	},
@@ -129,6 +150,11 @@ var errorCodeResponse = map[int]Error{
 		Description:    "The requested range cannot be satisfied.",
 		HTTPStatusCode: http.StatusRequestedRangeNotSatisfiable,
 	},
+	InvalidRequest: {
+		Code:           "InvalidRequest",
+		Description:    "The request is invalid.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	MalformedXML: {
 		Code:           "MalformedXML",
 		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
@@ -199,6 +225,111 @@ var errorCodeResponse = map[int]Error{
 		Description:    "The list of parts was not in ascending order. The parts list must be specified in order by part number.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	BucketNotEmpty: {
+		Code:           "BucketNotEmpty",
+		Description:    "The bucket you tried to delete is not empty.",
+		HTTPStatusCode: http.StatusConflict,
+	},
+	AuthorizationHeaderMalformed: {
+		Code:           "AuthorizationHeaderMalformed",
+		Description:    "The authorization header is malformed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	AuthorizationQueryParametersError: {
+		Code:           "AuthorizationQueryParametersError",
+		Description:    "The X-Amz-* query parameters of the presigned request are malformed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	ExpiredPresignRequest: {
+		Code:           "AccessDenied",
+		Description:    "Request has expired.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	RequestNotReadyYet: {
+		Code:           "AccessDenied",
+		Description:    "Request is not valid yet.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	MalformedPOSTRequest: {
+		Code:           "MalformedPOSTRequest",
+		Description:    "The body of your POST request is not well-formed multipart/form-data.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	InvalidPolicyDocument: {
+		Code:           "InvalidPolicyDocument",
+		Description:    "The content of the form does not meet the conditions specified in the policy document.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	PreconditionFailed: {
+		Code:           "PreconditionFailed",
+		Description:    "At least one of the pre-conditions you specified did not hold.",
+		HTTPStatusCode: http.StatusPreconditionFailed,
+	},
+	MalformedPolicy: {
+		Code:           "MalformedPolicy",
+		Description:    "The policy is not valid JSON or has an invalid statement for this bucket.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	NoSuchBucketPolicy: {
+		Code:           "NoSuchBucketPolicy",
+		Description:    "The bucket policy does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	NoSuchCORSConfiguration: {
+		Code:           "NoSuchCORSConfiguration",
+		Description:    "The CORS configuration does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	AccessForbidden: {
+		Code:           "AccessForbidden",
+		Description:    "CORSResponse: This CORS request is not allowed.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
+	NoSuchLifecycleConfiguration: {
+		Code:           "NoSuchLifecycleConfiguration",
+		Description:    "The lifecycle configuration does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	NoSuchVersion: {
+		Code:           "NoSuchVersion",
+		Description:    "The specified version does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	InvalidTag: {
+		Code:           "InvalidTag",
+		Description:    "The tag provided was not a valid tag.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	NoSuchTagSet: {
+		Code:           "NoSuchTagSet",
+		Description:    "The TagSet does not exist.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	NoSuchWebsiteConfiguration: {
+		Code:           "NoSuchWebsiteConfiguration",
+		Description:    "The specified bucket does not have a website configuration.",
+		HTTPStatusCode: http.StatusNotFound,
+	},
+	InvalidTargetBucketForLogging: {
+		Code:           "InvalidTargetBucketForLogging",
+		Description:    "The target bucket for logging does not exist.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	InvalidArgument: {
+		Code:           "InvalidArgument",
+		Description:    "Invalid Argument",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	MalformedACLError: {
+		Code:           "MalformedACLError",
+		Description:    "The XML you provided was not well-formed or did not validate against our published schema.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
+	XAmzContentSHA256Mismatch: {
+		Code:           "XAmzContentSHA256Mismatch",
+		Description:    "The provided 'x-amz-content-sha256' header does not match what was computed.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 }
 
 // errorCodeError provides errorCode to Error. It returns empty if the code provided is unknown
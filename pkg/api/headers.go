@@ -21,7 +21,9 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/minio/minio/pkg/storage/drivers"
 )
@@ -31,6 +33,24 @@ type encoder interface {
 	Encode(v interface{}) error
 }
 
+// standard http headers which are stored along with an object and sent back on GET and HEAD
+var storedObjectHeaders = []string{
+	"Content-Encoding",
+	"Content-Disposition",
+	"Cache-Control",
+	"Expires",
+}
+
+// response-* query parameters of a GET object request and the headers they override
+var responseOverrideHeaders = map[string]string{
+	"response-content-type":        "Content-Type",
+	"response-content-language":    "Content-Language",
+	"response-expires":             "Expires",
+	"response-cache-control":       "Cache-Control",
+	"response-content-disposition": "Content-Disposition",
+	"response-content-encoding":    "Content-Encoding",
+}
+
 //// helpers
 
 // Write http common headers
@@ -69,6 +89,78 @@ func setObjectHeaders(w http.ResponseWriter, metadata drivers.ObjectMetadata) {
 	// object related headers
 	w.Header().Set("ETag", "\""+metadata.Md5+"\"")
 	w.Header().Set("Last-Modified", lastModified)
+	// user defined and stored standard headers
+	for name, value := range metadata.Metadata {
+		w.Header().Set(name, value)
+	}
+	if len(metadata.Tags) > 0 {
+		w.Header().Set("X-Amz-Tagging-Count", strconv.Itoa(len(metadata.Tags)))
+	}
+	setObjectVersionHeaders(w, metadata)
+}
+
+// isRequestResponseOverride - verify if any of the response-* query parameters is set
+func isRequestResponseOverride(values url.Values) bool {
+	for param := range responseOverrideHeaders {
+		if _, ok := values[param]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getResponseOverrideMetadata - metadata with the headers sent back replaced by the response-* query parameters
+func getResponseOverrideMetadata(metadata drivers.ObjectMetadata, values url.Values) drivers.ObjectMetadata {
+	objectMetadata := make(map[string]string)
+	for name, value := range metadata.Metadata {
+		objectMetadata[name] = value
+	}
+	for param, name := range responseOverrideHeaders {
+		if _, ok := values[param]; !ok {
+			continue
+		}
+		if name == "Content-Type" {
+			metadata.ContentType = values.Get(param)
+			continue
+		}
+		objectMetadata[name] = values.Get(param)
+	}
+	metadata.Metadata = objectMetadata
+	return metadata
+}
+
+// setObjectVersionHeaders - version id of an object, the null version has none
+func setObjectVersionHeaders(w http.ResponseWriter, metadata drivers.ObjectMetadata) {
+	if metadata.VersionID != "" {
+		w.Header().Set("X-Amz-Version-Id", metadata.VersionID)
+	}
+	if metadata.IsDeleteMarker {
+		w.Header().Set("X-Amz-Delete-Marker", "true")
+	}
+}
+
+// getObjectMetadataHeaders - x-amz-meta-* and stored standard headers of a request, keyed by canonical header name
+func getObjectMetadataHeaders(header http.Header) map[string]string {
+	metadata := make(map[string]string)
+	for name, values := range header {
+		name = http.CanonicalHeaderKey(name)
+		if strings.HasPrefix(name, "X-Amz-Meta-") {
+			metadata[name] = strings.Join(values, ",")
+		}
+	}
+	for _, name := range storedObjectHeaders {
+		if value := header.Get(name); value != "" {
+			metadata[name] = value
+		}
+	}
+	if contentEncoding, ok := metadata["Content-Encoding"]; ok {
+		if contentEncoding = removeAWSChunkedEncoding(contentEncoding); contentEncoding == "" {
+			delete(metadata, "Content-Encoding")
+		} else {
+			metadata["Content-Encoding"] = contentEncoding
+		}
+	}
+	return metadata
 }
 
 // Write range object header
@@ -81,6 +173,15 @@ func setRangeObjectHeaders(w http.ResponseWriter, metadata drivers.ObjectMetadat
 	w.Header().Set("Content-Range", contentRange.getContentRange())
 }
 
+// Write multipart range object header, Content-Type and Content-Length describe the multipart/byteranges body
+func setMultipartRangeObjectHeaders(w http.ResponseWriter, metadata drivers.ObjectMetadata, ranges []*httpRange, boundary string) {
+	// set object headers
+	setObjectHeaders(w, metadata)
+	// override with the multipart body
+	w.Header().Set("Content-Type", "multipart/byteranges; boundary="+boundary)
+	w.Header().Set("Content-Length", strconv.FormatInt(getMultipartRangesSize(ranges, metadata.ContentType, boundary), 10))
+}
+
 func encodeSuccessResponse(response interface{}, acceptsType contentType) []byte {
 	var encoder encoder
 	var bytesBuffer bytes.Buffer
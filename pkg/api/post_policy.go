@@ -0,0 +1,284 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maximum size of a single non file form field
+const maxFormFieldSize = 64 * 1024
+
+// post policy errors
+var (
+	errMalformedPOSTRequest  = errors.New("Malformed multipart/form-data POST request")
+	errInvalidPolicyDocument = errors.New("Invalid policy document")
+	errPolicyExpired         = errors.New("Policy expired")
+	errPolicyMismatch        = errors.New("Form does not satisfy the policy conditions")
+	errMissingPolicySigning  = errors.New("Missing signature fields in form")
+)
+
+// isRequestPostPolicy - browser based upload to a bucket, signed through the policy in its form
+func isRequestPostPolicy(req *http.Request, object string) bool {
+	return req.Method == "POST" && object == "" && !isRequestDelete(req.URL.Query())
+}
+
+// postPolicyForm - form fields of a browser based POST upload, field names are lower cased
+type postPolicyForm map[string]string
+
+// postPolicyCondition - single "eq" or "starts-with" condition on a form field
+type postPolicyCondition struct {
+	operator string
+	field    string
+	value    string
+}
+
+// postPolicy - decoded policy document
+type postPolicy struct {
+	expiration         time.Time
+	conditions         []postPolicyCondition
+	contentLengthRange bool
+	minLength          int64
+	maxLength          int64
+}
+
+// form fields which need no policy condition of their own, bucket always comes from the request path
+// http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-HTTPPOSTConstructPolicy.html
+var postPolicyIgnoredFields = map[string]bool{
+	"bucket":          true,
+	"policy":          true,
+	"signature":       true,
+	"x-amz-signature": true,
+	"awsaccesskeyid":  true,
+	"file":            true,
+}
+
+// parsePostForm - read form fields up to the file part, fields after the file are ignored
+func parsePostForm(reader *multipart.Reader) (postPolicyForm, *multipart.Part, error) {
+	form := make(postPolicyForm)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			// io.EOF here means the form had no file field
+			return nil, nil, errMalformedPOSTRequest
+		}
+		name := strings.ToLower(part.FormName())
+		if name == "file" {
+			return form, part, nil
+		}
+		value, err := ioutil.ReadAll(io.LimitReader(part, maxFormFieldSize+1))
+		if err != nil || len(value) > maxFormFieldSize {
+			return nil, nil, errMalformedPOSTRequest
+		}
+		form[name] = string(value)
+	}
+}
+
+// parsePostPolicy - decode base64 encoded JSON policy document
+//
+//	{
+//	  "expiration": "2007-12-01T12:00:00.000Z",
+//	  "conditions": [
+//	    {"bucket": "johnsmith"},
+//	    ["starts-with", "$key", "user/eric/"],
+//	    ["content-length-range", 1, 1048576]
+//	  ]
+//	}
+func parsePostPolicy(encodedPolicy string) (*postPolicy, error) {
+	policyBytes, err := base64.StdEncoding.DecodeString(encodedPolicy)
+	if err != nil {
+		return nil, errInvalidPolicyDocument
+	}
+	var rawPolicy struct {
+		Expiration string        `json:"expiration"`
+		Conditions []interface{} `json:"conditions"`
+	}
+	if err := json.Unmarshal(policyBytes, &rawPolicy); err != nil {
+		return nil, errInvalidPolicyDocument
+	}
+	policy := new(postPolicy)
+	policy.expiration, err = time.Parse(time.RFC3339, rawPolicy.Expiration)
+	if err != nil {
+		return nil, errInvalidPolicyDocument
+	}
+	for _, rawCondition := range rawPolicy.Conditions {
+		switch condition := rawCondition.(type) {
+		case map[string]interface{}:
+			// {"field": "value"} is an exact match
+			for field, value := range condition {
+				stringValue, ok := value.(string)
+				if !ok {
+					return nil, errInvalidPolicyDocument
+				}
+				policy.conditions = append(policy.conditions, postPolicyCondition{
+					operator: "eq",
+					field:    strings.ToLower(field),
+					value:    stringValue,
+				})
+			}
+		case []interface{}:
+			if len(condition) != 3 {
+				return nil, errInvalidPolicyDocument
+			}
+			operator, ok := condition[0].(string)
+			if !ok {
+				return nil, errInvalidPolicyDocument
+			}
+			switch strings.ToLower(operator) {
+			case "content-length-range":
+				min, err := toPolicyInteger(condition[1])
+				if err != nil {
+					return nil, err
+				}
+				max, err := toPolicyInteger(condition[2])
+				if err != nil {
+					return nil, err
+				}
+				if min > max {
+					return nil, errInvalidPolicyDocument
+				}
+				policy.contentLengthRange = true
+				policy.minLength = min
+				policy.maxLength = max
+			case "eq", "starts-with":
+				field, ok := condition[1].(string)
+				if !ok || !strings.HasPrefix(field, "$") {
+					return nil, errInvalidPolicyDocument
+				}
+				value, ok := condition[2].(string)
+				if !ok {
+					return nil, errInvalidPolicyDocument
+				}
+				policy.conditions = append(policy.conditions, postPolicyCondition{
+					operator: strings.ToLower(operator),
+					field:    strings.ToLower(strings.TrimPrefix(field, "$")),
+					value:    value,
+				})
+			default:
+				return nil, errInvalidPolicyDocument
+			}
+		default:
+			return nil, errInvalidPolicyDocument
+		}
+	}
+	return policy, nil
+}
+
+// toPolicyInteger - content-length-range bounds may be sent either as numbers or strings
+func toPolicyInteger(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case float64:
+		return int64(v), nil
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, errInvalidPolicyDocument
+		}
+		return i, nil
+	}
+	return 0, errInvalidPolicyDocument
+}
+
+// checkPostPolicy - verify expiration and that the form satisfies every condition, each form field
+// apart from the signature fields and x-ignore-* must also be covered by a condition
+func checkPostPolicy(policy *postPolicy, form postPolicyForm) error {
+	if time.Now().UTC().After(policy.expiration) {
+		return errPolicyExpired
+	}
+	covered := make(map[string]bool)
+	for _, condition := range policy.conditions {
+		value := form[condition.field]
+		switch condition.operator {
+		case "eq":
+			if value != condition.value {
+				return errPolicyMismatch
+			}
+		case "starts-with":
+			if !strings.HasPrefix(value, condition.value) {
+				return errPolicyMismatch
+			}
+		}
+		covered[condition.field] = true
+	}
+	for field := range form {
+		if postPolicyIgnoredFields[field] || strings.HasPrefix(field, "x-ignore-") || covered[field] {
+			continue
+		}
+		return errPolicyMismatch
+	}
+	return nil
+}
+
+// getPostPolicyAccessKey - access key the policy of the form is signed with
+func getPostPolicyAccessKey(form postPolicyForm) string {
+	if form["x-amz-algorithm"] != "" {
+		return strings.Split(form["x-amz-credential"], "/")[0]
+	}
+	return form["awsaccesskeyid"]
+}
+
+// doesPolicySignatureMatch - verify the policy signature in the form against the signing user's secret key
+//
+// signature v4 signs the base64 policy with the scoped signing key, signature v2 with HMAC-SHA1 of the secret key
+func doesPolicySignatureMatch(users *userStore, form postPolicyForm) error {
+	var accessKey, signature, expectedSignature string
+	switch {
+	case form["x-amz-algorithm"] != "":
+		if form["x-amz-algorithm"] != authHeaderPrefix {
+			return errMissingPolicySigning
+		}
+		scope, err := getCredentialScope(form["x-amz-credential"])
+		if err != nil {
+			return err
+		}
+		date, err := time.Parse(timeFormat, form["x-amz-date"])
+		if err != nil || date.Format(yyyymmdd) != scope.date {
+			return errMalformedCredential
+		}
+		accessKey = strings.Split(form["x-amz-credential"], "/")[0]
+		secretKey, err := users.getSecretKey(accessKey)
+		if err != nil {
+			return err
+		}
+		signature = form["x-amz-signature"]
+		expectedSignature = getSignature(getSigningKey(secretKey, scope), form["policy"])
+	case form["awsaccesskeyid"] != "":
+		accessKey = form["awsaccesskeyid"]
+		secretKey, err := users.getSecretKey(accessKey)
+		if err != nil {
+			return err
+		}
+		signature = form["signature"]
+		expectedSignature = getSignatureV2(secretKey, form["policy"])
+	default:
+		return errMissingPolicySigning
+	}
+	if !hmac.Equal([]byte(signature), []byte(expectedSignature)) {
+		return errSignatureMismatch
+	}
+	return nil
+}
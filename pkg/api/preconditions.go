@@ -0,0 +1,66 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/minio/minio/pkg/storage/drivers"
+)
+
+// outcome of evaluating the conditional headers of a request
+const (
+	preconditionsMet = iota
+	preconditionNotModified
+	preconditionFailed
+)
+
+// evaluatePreconditions - evaluate If-Match, If-Unmodified-Since, If-None-Match and If-Modified-Since,
+// each name prefixed with headerPrefix, against the object in the order laid down by RFC 7232.
+// A matching If-Match ignores If-Unmodified-Since and a present If-None-Match ignores If-Modified-Since.
+func evaluatePreconditions(header http.Header, headerPrefix string, metadata drivers.ObjectMetadata) int {
+	// Last-Modified is sent with a granularity of seconds
+	lastModified := metadata.Created.UTC().Truncate(time.Second)
+	if ifMatch := header.Get(headerPrefix + "If-Match"); ifMatch != "" {
+		if !isETagMatch(ifMatch, metadata.Md5) {
+			return preconditionFailed
+		}
+	} else if ifUnmodifiedSince := header.Get(headerPrefix + "If-Unmodified-Since"); ifUnmodifiedSince != "" {
+		if t, err := http.ParseTime(ifUnmodifiedSince); err == nil && lastModified.After(t) {
+			return preconditionFailed
+		}
+	}
+	if ifNoneMatch := header.Get(headerPrefix + "If-None-Match"); ifNoneMatch != "" {
+		if isETagMatch(ifNoneMatch, metadata.Md5) {
+			return preconditionNotModified
+		}
+	} else if ifModifiedSince := header.Get(headerPrefix + "If-Modified-Since"); ifModifiedSince != "" {
+		if t, err := http.ParseTime(ifModifiedSince); err == nil && !lastModified.After(t) {
+			return preconditionNotModified
+		}
+	}
+	return preconditionsMet
+}
+
+// writeNotModifiedResponse - reply 304 along with the validators of the object
+func writeNotModifiedResponse(w http.ResponseWriter, metadata drivers.ObjectMetadata) {
+	w.Header().Set("Server", "Minio")
+	w.Header().Set("ETag", "\""+metadata.Md5+"\"")
+	w.Header().Set("Last-Modified", metadata.Created.Format(http.TimeFormat))
+	w.WriteHeader(http.StatusNotModified)
+}
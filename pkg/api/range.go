@@ -19,15 +19,22 @@ package api
 import (
 	"errors"
 	"fmt"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"strconv"
 	"strings"
 )
 
 const (
 	b = "bytes="
+	// requests for more ranges are served the whole object
+	maxRanges = 100
 )
 
+// errUnsatisfiableRange - range starts beyond the end of the object
+var errUnsatisfiableRange = errors.New("unsatisfiable range")
+
 // HttpRange specifies the byte range to be sent to the client.
 type httpRange struct {
 	start, length, size int64
@@ -38,21 +45,13 @@ func (r *httpRange) getContentRange() string {
 	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.start+r.length-1, r.size)
 }
 
-// Grab new range from request header
-func getRequestedRange(req *http.Request, size int64) (*httpRange, error) {
-	r := &httpRange{
-		start:  0,
-		length: 0,
-		size:   0,
-	}
-	r.size = size
-	if s := req.Header.Get("Range"); s != "" {
-		err := r.parseRange(s)
-		if err != nil {
-			return nil, err
-		}
+// Grab all ranges from request header, nil when the whole object is requested
+func getRequestedRanges(req *http.Request, size int64) ([]*httpRange, error) {
+	s := req.Header.Get("Range")
+	if s == "" {
+		return nil, nil
 	}
-	return r, nil
+	return parseRanges(s, size)
 }
 
 func (r *httpRange) parse(ra string) error {
@@ -65,9 +64,12 @@ func (r *httpRange) parse(ra string) error {
 		// If no start is specified, end specifies the
 		// range start relative to the end of the file.
 		i, err := strconv.ParseInt(end, 10, 64)
-		if err != nil {
+		if err != nil || i < 0 {
 			return errors.New("invalid range")
 		}
+		if i == 0 {
+			return errUnsatisfiableRange
+		}
 		if i > r.size {
 			i = r.size
 		}
@@ -75,9 +77,12 @@ func (r *httpRange) parse(ra string) error {
 		r.length = r.size - r.start
 	} else {
 		i, err := strconv.ParseInt(start, 10, 64)
-		if err != nil || i > r.size || i < 0 {
+		if err != nil || i < 0 {
 			return errors.New("invalid range")
 		}
+		if i >= r.size {
+			return errUnsatisfiableRange
+		}
 		r.start = i
 		if end == "" {
 			// If no end is specified, range extends to end of the file.
@@ -93,30 +98,80 @@ func (r *httpRange) parse(ra string) error {
 			r.length = i - r.start + 1
 		}
 	}
+	if r.length <= 0 {
+		return errors.New("invalid range")
+	}
 	return nil
 }
 
-// parseRange parses a Range header string as per RFC 2616.
-func (r *httpRange) parseRange(s string) error {
+// parseRanges parses a Range header string as per RFC 2616, ranges beyond the end of the object are
+// left out and at least one range must be satisfiable. Too many ranges, or ranges adding up to more
+// than the object itself, are served the whole object rather than a response larger than the object
+func parseRanges(s string, size int64) ([]*httpRange, error) {
 	if s == "" {
-		return errors.New("header not present")
+		return nil, errors.New("header not present")
 	}
 	if !strings.HasPrefix(s, b) {
-		return errors.New("invalid range")
+		return nil, errors.New("invalid range")
 	}
-
-	ras := strings.Split(s[len(b):], ",")
-	if len(ras) == 0 {
-		return errors.New("invalid request")
+	var ranges []*httpRange
+	var rangesSize int64
+	unsatisfiable := false
+	for _, ra := range strings.Split(s[len(b):], ",") {
+		ra = strings.TrimSpace(ra)
+		if ra == "" {
+			// tolerate empty elements of the list, "bytes=0-1,,4-5"
+			continue
+		}
+		r := &httpRange{size: size}
+		switch err := r.parse(ra); err {
+		case nil:
+			ranges = append(ranges, r)
+			rangesSize += r.length
+		case errUnsatisfiableRange:
+			unsatisfiable = true
+		default:
+			return nil, err
+		}
+	}
+	if len(ranges) == 0 {
+		if unsatisfiable {
+			return nil, errUnsatisfiableRange
+		}
+		return nil, errors.New("invalid range")
 	}
-	// Just pick the first one and ignore the rest, we only support one range per object
-	if len(ras) > 1 {
-		return errors.New("multiple ranges specified")
+	if len(ranges) > maxRanges || rangesSize > size {
+		return nil, nil
 	}
+	return ranges, nil
+}
 
-	ra := strings.TrimSpace(ras[0])
-	if ra == "" {
-		return errors.New("invalid range")
+// getMimeHeader - part header of the range in a multipart/byteranges response
+func (r *httpRange) getMimeHeader(contentType string) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Range": {r.getContentRange()},
+		"Content-Type":  {contentType},
+	}
+}
+
+// countingWriter counts the bytes written to it
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+// getMultipartRangesSize - exact length of the multipart/byteranges body for the ranges,
+// computed ahead so that Content-Length can be sent before streaming the parts
+func getMultipartRangesSize(ranges []*httpRange, contentType, boundary string) int64 {
+	var w countingWriter
+	multipartWriter := multipart.NewWriter(&w)
+	multipartWriter.SetBoundary(boundary)
+	for _, r := range ranges {
+		multipartWriter.CreatePart(r.getMimeHeader(contentType))
+		w += countingWriter(r.length)
 	}
-	return r.parse(ra)
+	multipartWriter.Close()
+	return int64(w)
 }
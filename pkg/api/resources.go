@@ -17,8 +17,11 @@
 package api
 
 import (
+	"encoding/base64"
+	"errors"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/minio/minio/pkg/storage/drivers"
 )
@@ -33,6 +36,30 @@ func getBucketResources(values url.Values) (v drivers.BucketResourcesMetadata) {
 	return
 }
 
+// parse bucket url queries for ?list-type=2, a continuation token takes the place of start-after
+func getBucketResourcesV2(values url.Values) (v drivers.BucketResourcesMetadata, err error) {
+	v.Prefix = values.Get("prefix")
+	v.Marker = values.Get("start-after")
+	v.Maxkeys, _ = strconv.Atoi(values.Get("max-keys"))
+	v.Delimiter = values.Get("delimiter")
+	v.EncodingType = values.Get("encoding-type")
+	if _, ok := values["continuation-token"]; ok {
+		v.Marker, err = decodeContinuationToken(values.Get("continuation-token"))
+	}
+	return
+}
+
+// parse bucket url queries for ?versions
+func getBucketVersionResources(values url.Values) (v drivers.BucketResourcesMetadata) {
+	v.Prefix = values.Get("prefix")
+	v.Marker = values.Get("key-marker")
+	v.VersionIDMarker = values.Get("version-id-marker")
+	v.Maxkeys, _ = strconv.Atoi(values.Get("max-keys"))
+	v.Delimiter = values.Get("delimiter")
+	v.EncodingType = values.Get("encoding-type")
+	return
+}
+
 // part bucket url queries for ?uploads
 func getBucketMultipartResources(values url.Values) (v drivers.BucketMultipartResourcesMetadata) {
 	v.Prefix = values.Get("prefix")
@@ -59,8 +86,103 @@ func isRequestUploads(values url.Values) bool {
 	return ok
 }
 
+// check if req query values carry delete resource
+func isRequestDelete(values url.Values) bool {
+	_, ok := values["delete"]
+	return ok
+}
+
+// check if req query values carry policy resource
+func isRequestBucketPolicy(values url.Values) bool {
+	_, ok := values["policy"]
+	return ok
+}
+
+// check if req query values carry cors resource
+func isRequestBucketCORS(values url.Values) bool {
+	_, ok := values["cors"]
+	return ok
+}
+
+// check if req query values carry lifecycle resource
+func isRequestBucketLifecycle(values url.Values) bool {
+	_, ok := values["lifecycle"]
+	return ok
+}
+
 // check if req query values carry acl resource
-func isRequestBucketACL(values url.Values) bool {
+func isRequestACL(values url.Values) bool {
 	_, ok := values["acl"]
 	return ok
 }
+
+// check if req query values carry versioning resource
+func isRequestBucketVersioning(values url.Values) bool {
+	_, ok := values["versioning"]
+	return ok
+}
+
+// check if req query values carry versions resource
+func isRequestBucketVersions(values url.Values) bool {
+	_, ok := values["versions"]
+	return ok
+}
+
+// check if req query values carry tagging resource
+func isRequestTagging(values url.Values) bool {
+	_, ok := values["tagging"]
+	return ok
+}
+
+// check if req query values carry website resource
+func isRequestBucketWebsite(values url.Values) bool {
+	_, ok := values["website"]
+	return ok
+}
+
+// check if req query values carry notification resource
+func isRequestBucketNotification(values url.Values) bool {
+	_, ok := values["notification"]
+	return ok
+}
+
+// check if req query values carry logging resource
+func isRequestBucketLogging(values url.Values) bool {
+	_, ok := values["logging"]
+	return ok
+}
+
+// check if req query values ask for the version 2 listing
+func isRequestListObjectsV2(values url.Values) bool {
+	return values.Get("list-type") == "2"
+}
+
+var errInvalidContinuationToken = errors.New("Invalid continuation token")
+
+// encodeContinuationToken - opaque token resuming a listing after marker
+func encodeContinuationToken(marker string) string {
+	return base64.StdEncoding.EncodeToString([]byte(marker))
+}
+
+// decodeContinuationToken - marker a listing resumes after
+func decodeContinuationToken(token string) (string, error) {
+	marker, err := base64.StdEncoding.DecodeString(token)
+	if err != nil || len(marker) == 0 {
+		return "", errInvalidContinuationToken
+	}
+	return string(marker), nil
+}
+
+// isValidEncodingType - keys in listings are either returned as they are or url encoded
+func isValidEncodingType(encodingType string) bool {
+	return encodingType == "" || encodingType == "url"
+}
+
+// encodeName - key, prefix, marker or delimiter in a listing, url encoded for encoding-type=url so
+// that names with characters not allowed in XML can be listed. Slashes are left as they are.
+func encodeName(name, encodingType string) string {
+	if encodingType != "url" {
+		return name
+	}
+	return strings.Replace(url.QueryEscape(name), "%2F", "/", -1)
+}
@@ -0,0 +1,148 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/minio/minio/pkg/utils/crypto/sha1"
+)
+
+// Sub-resources which are part of the signature v2 canonicalized resource
+// http://docs.aws.amazon.com/AmazonS3/latest/dev/RESTAuthentication.html#ConstructingTheCanonicalizedResourceElement
+var signV2SubResources = map[string]bool{
+	"acl":                          true,
+	"cors":                         true,
+	"delete":                       true,
+	"lifecycle":                    true,
+	"location":                     true,
+	"logging":                      true,
+	"notification":                 true,
+	"partNumber":                   true,
+	"policy":                       true,
+	"requestPayment":               true,
+	"response-cache-control":       true,
+	"response-content-disposition": true,
+	"response-content-encoding":    true,
+	"response-content-language":    true,
+	"response-content-type":        true,
+	"response-expires":             true,
+	"tagging":                      true,
+	"torrent":                      true,
+	"uploadId":                     true,
+	"uploads":                      true,
+	"versionId":                    true,
+	"versioning":                   true,
+	"versions":                     true,
+	"website":                      true,
+}
+
+// getCanonicalizedAmzHeadersV2 - sorted "x-amz-name:value\n" for every x-amz-* header
+func getCanonicalizedAmzHeadersV2(header http.Header) string {
+	var names []string
+	values := make(map[string]string)
+	for name, value := range header {
+		name = strings.ToLower(name)
+		if !strings.HasPrefix(name, "x-amz-") {
+			continue
+		}
+		var trimmedValues []string
+		for _, v := range value {
+			trimmedValues = append(trimmedValues, strings.TrimSpace(v))
+		}
+		names = append(names, name)
+		values[name] = strings.Join(trimmedValues, ",")
+	}
+	sort.Strings(names)
+	var canonicalHeaders bytes.Buffer
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + values[name] + "\n")
+	}
+	return canonicalHeaders.String()
+}
+
+// getCanonicalizedResourceV2 - request path followed by the sorted sub-resources
+func getCanonicalizedResourceV2(r *http.Request) string {
+	resource := r.URL.EscapedPath()
+	if resource == "" {
+		resource = "/"
+	}
+	query := r.URL.Query()
+	var subResources []string
+	for name := range query {
+		if signV2SubResources[name] {
+			subResources = append(subResources, name)
+		}
+	}
+	if len(subResources) == 0 {
+		return resource
+	}
+	sort.Strings(subResources)
+	var params []string
+	for _, name := range subResources {
+		if value := query.Get(name); value != "" {
+			params = append(params, name+"="+value)
+			continue
+		}
+		params = append(params, name)
+	}
+	return resource + "?" + strings.Join(params, "&")
+}
+
+// getStringToSignV2 - generate string to sign
+//
+// <HTTPMethod>\n
+// <Content-MD5>\n
+// <Content-Type>\n
+// <Date>\n
+// <CanonicalizedAmzHeaders>
+// <CanonicalizedResource>
+func getStringToSignV2(r *http.Request) string {
+	date := r.Header.Get("Date")
+	// Date is replaced by x-amz-date, which is then part of the amz headers
+	if r.Header.Get("X-Amz-Date") != "" {
+		date = ""
+	}
+	stringToSign := r.Method + "\n"
+	stringToSign = stringToSign + r.Header.Get("Content-MD5") + "\n"
+	stringToSign = stringToSign + r.Header.Get("Content-Type") + "\n"
+	stringToSign = stringToSign + date + "\n"
+	stringToSign = stringToSign + getCanonicalizedAmzHeadersV2(r.Header)
+	stringToSign = stringToSign + getCanonicalizedResourceV2(r)
+	return stringToSign
+}
+
+// getSignatureV2 - base64 encoded HMAC-SHA1 of the string to sign
+func getSignatureV2(secretKey, stringToSign string) string {
+	hash := hmac.New(sha1.New, []byte(secretKey))
+	hash.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
+}
+
+// doesSignatureV2Match - verify the signature v2 auth header against the user's secret key
+func doesSignatureV2Match(secretKey string, r *http.Request, a *auth) error {
+	signature := getSignatureV2(secretKey, getStringToSignV2(r))
+	if !hmac.Equal([]byte(signature), []byte(a.signature)) {
+		return errSignatureMismatch
+	}
+	return nil
+}
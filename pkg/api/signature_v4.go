@@ -0,0 +1,382 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Signature v4 constants - http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+const (
+	yyyymmdd         = "20060102"
+	scopeTerminator  = "aws4_request"
+	scopeService     = "s3"
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+)
+
+// signature v4 verification errors
+var (
+	errMalformedCredential = errors.New("Malformed credential scope in auth header")
+	errHostNotSigned       = errors.New("Host header is not signed")
+	errUnsignedHeaders     = errors.New("Headers present in the request are not signed")
+	errMissingSignedHeader = errors.New("Signed header missing from the request")
+	errSignatureMismatch   = errors.New("Signature does not match")
+	errMissingContentHash  = errors.New("Payload hash missing from a request with a body")
+	errInvalidContentHash  = errors.New("Invalid payload hash")
+)
+
+// contentSHA256Mismatch - payload of the request does not hash to the signed x-amz-content-sha256
+type contentSHA256Mismatch struct{}
+
+func (e contentSHA256Mismatch) Error() string {
+	return "Payload does not match x-amz-content-sha256"
+}
+
+// sha256Reader - verifies the payload read through it against its expected hash, once all of it was read
+type sha256Reader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	expected string
+	size     int64
+	read     int64
+}
+
+// newSHA256Reader - verify a payload of size bytes, -1 when unknown, against the hex encoded sha256 expected
+func newSHA256Reader(body io.ReadCloser, expected string, size int64) io.ReadCloser {
+	return &sha256Reader{ReadCloser: body, hash: sha256.New(), expected: expected, size: size}
+}
+
+// Read - drivers may stop reading at the size of the payload without ever reaching io.EOF, the last
+// data read is held back on a mismatch so that such a read never completes
+func (r *sha256Reader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	r.read += int64(n)
+	if err == io.EOF || (err == nil && r.read == r.size) {
+		if hex.EncodeToString(r.hash.Sum(nil)) != r.expected {
+			return 0, contentSHA256Mismatch{}
+		}
+	}
+	return n, err
+}
+
+// credentialScope - date, region, service and terminator part of the credential
+type credentialScope struct {
+	date    string
+	region  string
+	service string
+	request string
+}
+
+// getCredentialScope - parse "<accesskey>/<date>/<region>/<service>/aws4_request"
+func getCredentialScope(credential string) (credentialScope, error) {
+	credentialFields := strings.Split(strings.TrimSpace(credential), "/")
+	if len(credentialFields) != 5 {
+		return credentialScope{}, errMalformedCredential
+	}
+	scope := credentialScope{
+		date:    credentialFields[1],
+		region:  credentialFields[2],
+		service: credentialFields[3],
+		request: credentialFields[4],
+	}
+	if scope.service != scopeService || scope.request != scopeTerminator {
+		return credentialScope{}, errMalformedCredential
+	}
+	if _, err := time.Parse(yyyymmdd, scope.date); err != nil {
+		return credentialScope{}, errMalformedCredential
+	}
+	return scope, nil
+}
+
+// String - scope as it appears in the string to sign
+func (s credentialScope) String() string {
+	return strings.Join([]string{s.date, s.region, s.service, s.request}, "/")
+}
+
+// sumHMAC - HMAC-SHA256 of data with key
+func sumHMAC(key []byte, data []byte) []byte {
+	hash := hmac.New(sha256.New, key)
+	hash.Write(data)
+	return hash.Sum(nil)
+}
+
+// sumSHA256Hex - hex encoded SHA256 of data
+func sumSHA256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// uriEncode - encode every byte except the unreserved characters 'A'-'Z', 'a'-'z', '0'-'9', '-', '.', '_' and '~',
+// '/' is left as is only when encoding a path
+func uriEncode(input string, isPath bool) string {
+	var encoded bytes.Buffer
+	for i := 0; i < len(input); i++ {
+		ch := input[i]
+		switch {
+		case 'A' <= ch && ch <= 'Z', 'a' <= ch && ch <= 'z', '0' <= ch && ch <= '9':
+			encoded.WriteByte(ch)
+		case ch == '-' || ch == '.' || ch == '_' || ch == '~':
+			encoded.WriteByte(ch)
+		case ch == '/' && isPath:
+			encoded.WriteByte(ch)
+		default:
+			encoded.WriteString("%" + strings.ToUpper(hex.EncodeToString([]byte{ch})))
+		}
+	}
+	return encoded.String()
+}
+
+// getCanonicalQueryString - sorted and encoded query parameters, X-Amz-Signature itself is never signed
+func getCanonicalQueryString(query url.Values) string {
+	var keys []string
+	for key := range query {
+		if key == "X-Amz-Signature" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var params []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, uriEncode(key, false)+"="+uriEncode(value, false))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// getHeaderValue - value of a signed header as the client would have seen it
+func getHeaderValue(r *http.Request, name string) (string, bool) {
+	switch name {
+	case "host":
+		return r.Host, true
+	case "content-length":
+		if r.Header.Get("Content-Length") == "" {
+			return strconv.FormatInt(r.ContentLength, 10), true
+		}
+	}
+	values, ok := r.Header[http.CanonicalHeaderKey(name)]
+	if !ok {
+		return "", false
+	}
+	var trimmedValues []string
+	for _, value := range values {
+		// trim and collapse sequential spaces into one
+		trimmedValues = append(trimmedValues, strings.Join(strings.Fields(value), " "))
+	}
+	return strings.Join(trimmedValues, ","), true
+}
+
+// getCanonicalHeaders - "name:value\n" for every signed header
+func getCanonicalHeaders(r *http.Request, signedHeaders []string) (string, error) {
+	var canonicalHeaders bytes.Buffer
+	for _, name := range signedHeaders {
+		value, ok := getHeaderValue(r, name)
+		if !ok {
+			return "", errMissingSignedHeader
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	return canonicalHeaders.String(), nil
+}
+
+// verifySignedHeaders - host and every x-amz-* header sent along must be signed
+func verifySignedHeaders(r *http.Request, signedHeaders []string) error {
+	signed := make(map[string]bool)
+	for _, name := range signedHeaders {
+		signed[name] = true
+	}
+	if !signed["host"] {
+		return errHostNotSigned
+	}
+	for name := range r.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") && !signed[name] {
+			return errUnsignedHeaders
+		}
+	}
+	return nil
+}
+
+// getRequestPath - path of the request as the client sent it, virtual hosted style requests are
+// rewritten into path style requests once received
+func getRequestPath(r *http.Request) string {
+	if r.RequestURI != "" {
+		if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+			return u.Path
+		}
+	}
+	return r.URL.Path
+}
+
+// getCanonicalRequest - generate canonical request
+//
+// <HTTPMethod>\n
+// <CanonicalURI>\n
+// <CanonicalQueryString>\n
+// <CanonicalHeaders>\n
+// <SignedHeaders>\n
+// <HashedPayload>
+func getCanonicalRequest(r *http.Request, signedHeaders []string, hashedPayload string) (string, error) {
+	canonicalHeaders, err := getCanonicalHeaders(r, signedHeaders)
+	if err != nil {
+		return "", err
+	}
+	canonicalURI := uriEncode(getRequestPath(r), true)
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		canonicalURI,
+		getCanonicalQueryString(r.URL.Query()),
+		canonicalHeaders,
+		strings.Join(signedHeaders, ";"),
+		hashedPayload,
+	}, "\n")
+	return canonicalRequest, nil
+}
+
+// getStringToSign - string to sign from the canonical request, request time and credential scope
+func getStringToSign(canonicalRequest string, t time.Time, scope credentialScope) string {
+	stringToSign := authHeaderPrefix + "\n" + t.Format(timeFormat) + "\n"
+	stringToSign = stringToSign + scope.String() + "\n"
+	stringToSign = stringToSign + sumSHA256Hex([]byte(canonicalRequest))
+	return stringToSign
+}
+
+// getSigningKey - derive the scoped signing key from the secret key
+func getSigningKey(secretKey string, scope credentialScope) []byte {
+	date := sumHMAC([]byte("AWS4"+secretKey), []byte(scope.date))
+	region := sumHMAC(date, []byte(scope.region))
+	service := sumHMAC(region, []byte(scope.service))
+	signingKey := sumHMAC(service, []byte(scope.request))
+	return signingKey
+}
+
+// getSignature - final hex encoded signature
+func getSignature(signingKey []byte, stringToSign string) string {
+	return hex.EncodeToString(sumHMAC(signingKey, []byte(stringToSign)))
+}
+
+// getHashedPayload - payload hash the client signed, as sent in x-amz-content-sha256, which is only
+// optional for requests without a body
+func getHashedPayload(r *http.Request) (string, error) {
+	hashedPayload := r.Header.Get("X-Amz-Content-Sha256")
+	switch {
+	case hashedPayload == "":
+		if r.ContentLength != 0 {
+			return "", errMissingContentHash
+		}
+		return emptyPayloadHash, nil
+	case hashedPayload == unsignedPayload || hashedPayload == streamingContentSHA256:
+		return hashedPayload, nil
+	}
+	if decoded, err := hex.DecodeString(hashedPayload); err != nil || len(decoded) != sha256.Size {
+		return "", errInvalidContentHash
+	}
+	return hashedPayload, nil
+}
+
+// verifyPayload - hash the body of a signed request as it is read, against the payload hash of the signature
+func verifyPayload(r *http.Request) {
+	hashedPayload := r.Header.Get("X-Amz-Content-Sha256")
+	if hashedPayload == "" || hashedPayload == unsignedPayload || hashedPayload == streamingContentSHA256 {
+		return
+	}
+	if r.Body == nil {
+		return
+	}
+	r.Body = newSHA256Reader(r.Body, strings.ToLower(hashedPayload), r.ContentLength)
+}
+
+// doesSignatureV4Match - verify the signature v4 auth header against the user's secret key
+func doesSignatureV4Match(secretKey string, r *http.Request, a *auth) error {
+	scope, err := getCredentialScope(a.credential)
+	if err != nil {
+		return err
+	}
+	date, err := getDate(r)
+	if err != nil {
+		return err
+	}
+	date = date.UTC()
+	if date.Format(yyyymmdd) != scope.date {
+		return errMalformedCredential
+	}
+	signedHeaders := strings.Split(strings.TrimSpace(a.signedheaders), ";")
+	if err := verifySignedHeaders(r, signedHeaders); err != nil {
+		return err
+	}
+	hashedPayload, err := getHashedPayload(r)
+	if err != nil {
+		return err
+	}
+	canonicalRequest, err := getCanonicalRequest(r, signedHeaders, hashedPayload)
+	if err != nil {
+		return err
+	}
+	stringToSign := getStringToSign(canonicalRequest, date, scope)
+	signature := getSignature(getSigningKey(secretKey, scope), stringToSign)
+	if !hmac.Equal([]byte(signature), []byte(strings.TrimSpace(a.signature))) {
+		return errSignatureMismatch
+	}
+	return nil
+}
+
+// doesPresignedSignatureV4Match - verify the presigned request query against the user's secret key
+func doesPresignedSignatureV4Match(secretKey string, r *http.Request, a *auth) error {
+	scope, err := getCredentialScope(a.credential)
+	if err != nil {
+		return err
+	}
+	date := a.date.UTC()
+	if date.Format(yyyymmdd) != scope.date {
+		return errMalformedCredential
+	}
+	signedHeaders := strings.Split(strings.TrimSpace(a.signedheaders), ";")
+	if err := verifySignedHeaders(r, signedHeaders); err != nil {
+		return err
+	}
+	// payload of a presigned request is never part of the signature
+	canonicalRequest, err := getCanonicalRequest(r, signedHeaders, unsignedPayload)
+	if err != nil {
+		return err
+	}
+	stringToSign := getStringToSign(canonicalRequest, date, scope)
+	signature := getSignature(getSigningKey(secretKey, scope), stringToSign)
+	if !hmac.Equal([]byte(signature), []byte(strings.TrimSpace(a.signature))) {
+		return errSignatureMismatch
+	}
+	return nil
+}
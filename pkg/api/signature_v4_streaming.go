@@ -0,0 +1,218 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Streaming signature v4 constants - http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-streaming.html
+const (
+	streamingContentSHA256 = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	streamingPayloadPrefix = "AWS4-HMAC-SHA256-PAYLOAD"
+	chunkSignaturePrefix   = "chunk-signature="
+	awsChunkedEncoding     = "aws-chunked"
+	// chunks are verified before their data is handed on, which keeps every chunk in memory
+	maxChunkSize       = 16 * 1024 * 1024
+	maxChunkHeaderSize = 4096
+)
+
+var errStreamingNotSigned = errors.New("Streaming payload without a signature v4 auth header")
+
+// chunkSignatureMismatch - signature of a chunk does not match the seed signature and its data
+type chunkSignatureMismatch struct{}
+
+func (e chunkSignatureMismatch) Error() string {
+	return "Chunk signature does not match"
+}
+
+// malformedChunkedEncoding - body of the request is not a valid aws-chunked payload of the decoded content length
+type malformedChunkedEncoding struct{}
+
+func (e malformedChunkedEncoding) Error() string {
+	return "Malformed aws-chunked encoding"
+}
+
+// isRequestStreamingSignatureV4 - verify if the payload of the request is chunk signed
+func isRequestStreamingSignatureV4(req *http.Request) bool {
+	return req.Header.Get("X-Amz-Content-Sha256") == streamingContentSHA256
+}
+
+// removeAWSChunkedEncoding - content encodings other than aws-chunked, which only describes the transfer
+func removeAWSChunkedEncoding(contentEncoding string) string {
+	var encodings []string
+	for _, encoding := range strings.Split(contentEncoding, ",") {
+		encoding = strings.TrimSpace(encoding)
+		if encoding != "" && encoding != awsChunkedEncoding {
+			encodings = append(encodings, encoding)
+		}
+	}
+	return strings.Join(encodings, ",")
+}
+
+// getRequestPayload - reader of the object data in the request body, chunk signed payloads are decoded and verified
+func getRequestPayload(w http.ResponseWriter, req *http.Request, users *userStore, size int64, acceptsContentType contentType) (io.Reader, bool) {
+	if !isRequestStreamingSignatureV4(req) {
+		return req.Body, true
+	}
+	payload, err := newSignV4ChunkedReader(req, users, size)
+	switch err {
+	case nil:
+		return payload, true
+	case errInvalidAccessKey:
+		writeErrorResponse(w, req, InvalidAccessKeyID, acceptsContentType, req.URL.Path)
+	case errStreamingNotSigned:
+		writeErrorResponse(w, req, InvalidRequest, acceptsContentType, req.URL.Path)
+	case errMalformedCredential:
+		writeErrorResponse(w, req, AuthorizationHeaderMalformed, acceptsContentType, req.URL.Path)
+	default:
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+	}
+	return nil, false
+}
+
+// signV4ChunkedReader - decodes an aws-chunked body, every chunk is verified against the signature
+// of the previous chunk, starting with the seed signature of the auth header
+type signV4ChunkedReader struct {
+	reader        *bufio.Reader
+	signingKey    []byte
+	date          time.Time
+	scope         credentialScope
+	prevSignature string
+	remaining     int64
+	chunk         []byte
+	err           error
+}
+
+// newSignV4ChunkedReader - reader of the decoded payload of a streaming signature v4 request of the given decoded size,
+// the seed signature itself was verified along with the request
+func newSignV4ChunkedReader(req *http.Request, users *userStore, size int64) (io.Reader, error) {
+	auth, err := stripAuth(req)
+	if err != nil {
+		if err == errInvalidAccessKey {
+			return nil, err
+		}
+		return nil, errStreamingNotSigned
+	}
+	if auth.prefix != authHeaderPrefix {
+		return nil, errStreamingNotSigned
+	}
+	scope, err := getCredentialScope(auth.credential)
+	if err != nil {
+		return nil, err
+	}
+	date, err := getDate(req)
+	if err != nil {
+		return nil, err
+	}
+	secretKey, err := users.getSecretKey(auth.accessKey)
+	if err != nil {
+		return nil, err
+	}
+	return &signV4ChunkedReader{
+		reader:        bufio.NewReaderSize(req.Body, maxChunkHeaderSize),
+		signingKey:    getSigningKey(secretKey, scope),
+		date:          date.UTC(),
+		scope:         scope,
+		prevSignature: strings.TrimSpace(auth.signature),
+		remaining:     size,
+	}, nil
+}
+
+// Read - read decoded data of verified chunks
+func (cr *signV4ChunkedReader) Read(p []byte) (int, error) {
+	for len(cr.chunk) == 0 {
+		if cr.err != nil {
+			return 0, cr.err
+		}
+		cr.err = cr.readChunk()
+	}
+	n := copy(p, cr.chunk)
+	cr.chunk = cr.chunk[n:]
+	return n, nil
+}
+
+// getChunkSignature - signature of the chunk data chained to the signature of the previous chunk
+//
+// AWS4-HMAC-SHA256-PAYLOAD\n
+// <Timestamp>\n
+// <CredentialScope>\n
+// <PreviousSignature>\n
+// <HashOfEmptyString>\n
+// <HashOfChunkData>
+func (cr *signV4ChunkedReader) getChunkSignature(data []byte) string {
+	stringToSign := strings.Join([]string{
+		streamingPayloadPrefix,
+		cr.date.Format(timeFormat),
+		cr.scope.String(),
+		cr.prevSignature,
+		emptyPayloadHash,
+		sumSHA256Hex(data),
+	}, "\n")
+	return getSignature(cr.signingKey, stringToSign)
+}
+
+// readChunk - read and verify "<hex size>;chunk-signature=<signature>\r\n<data>\r\n", the final chunk is empty
+func (cr *signV4ChunkedReader) readChunk() error {
+	header, err := cr.reader.ReadSlice('\n')
+	if err != nil || !bytes.HasSuffix(header, []byte("\r\n")) {
+		return malformedChunkedEncoding{}
+	}
+	headerFields := strings.SplitN(string(header[:len(header)-2]), ";", 2)
+	if len(headerFields) != 2 || !strings.HasPrefix(headerFields[1], chunkSignaturePrefix) {
+		return malformedChunkedEncoding{}
+	}
+	size, err := strconv.ParseInt(headerFields[0], 16, 64)
+	if err != nil || size < 0 || size > maxChunkSize || size > cr.remaining {
+		return malformedChunkedEncoding{}
+	}
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(cr.reader, data); err != nil || !bytes.HasSuffix(data, []byte("\r\n")) {
+		return malformedChunkedEncoding{}
+	}
+	data = data[:size]
+	signature := strings.TrimPrefix(headerFields[1], chunkSignaturePrefix)
+	if !hmac.Equal([]byte(cr.getChunkSignature(data)), []byte(signature)) {
+		return chunkSignatureMismatch{}
+	}
+	cr.prevSignature = signature
+	if size == 0 {
+		if cr.remaining != 0 {
+			return malformedChunkedEncoding{}
+		}
+		return io.EOF
+	}
+	cr.remaining -= size
+	if cr.remaining == 0 {
+		// drivers stop reading at the decoded size, verify the final chunk along with the last data
+		if err := cr.readChunk(); err != io.EOF {
+			return err
+		}
+		cr.chunk = data
+		return io.EOF
+	}
+	cr.chunk = data
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maximum size of a tagging document
+const maxTaggingSize = 64 * 1024
+
+// limits of a tag set
+const (
+	maxObjectTags     = 10
+	maxBucketTags     = 50
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// bucket resource name the tags of a bucket are kept under
+const bucketTaggingResource = "tagging"
+
+var errMalformedTagging = errors.New("Malformed tagging document")
+var errInvalidTag = errors.New("Invalid tag")
+
+// parseTagging - decode and validate a tagging document, with at most maxTags tags
+//
+//	<Tagging>
+//	  <TagSet>
+//	    <Tag><Key>team</Key><Value>storage</Value></Tag>
+//	  </TagSet>
+//	</Tagging>
+func parseTagging(data []byte, maxTags int) (map[string]string, error) {
+	tagging := new(Tagging)
+	if err := xml.Unmarshal(data, tagging); err != nil {
+		return nil, errMalformedTagging
+	}
+	if len(tagging.TagSet.Tag) > maxTags {
+		return nil, errInvalidTag
+	}
+	tags := make(map[string]string)
+	for _, tag := range tagging.TagSet.Tag {
+		if err := addTag(tags, tag.Key, tag.Value); err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// parseTaggingHeader - tags of the x-amz-tagging header, url encoded as "key1=value1&key2=value2"
+func parseTaggingHeader(value string) (map[string]string, error) {
+	values, err := url.ParseQuery(value)
+	if err != nil {
+		return nil, errInvalidTag
+	}
+	if len(values) > maxObjectTags {
+		return nil, errInvalidTag
+	}
+	tags := make(map[string]string)
+	for key, tagValues := range values {
+		if len(tagValues) != 1 {
+			return nil, errInvalidTag
+		}
+		if err := addTag(tags, key, tagValues[0]); err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// addTag - add a tag to a tag set, keys are unique and the "aws:" prefix is reserved
+func addTag(tags map[string]string, key, value string) error {
+	if key == "" || utf8.RuneCountInString(key) > maxTagKeyLength || utf8.RuneCountInString(value) > maxTagValueLength {
+		return errInvalidTag
+	}
+	if strings.HasPrefix(key, "aws:") {
+		return errInvalidTag
+	}
+	if _, ok := tags[key]; ok {
+		return errInvalidTag
+	}
+	tags[key] = value
+	return nil
+}
+
+// generateTagging - tagging document of a tag set, tags sorted by key
+func generateTagging(tags map[string]string) Tagging {
+	var keys []string
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tagging := Tagging{}
+	for _, key := range keys {
+		tagging.TagSet.Tag = append(tagging.TagSet.Tag, Tag{Key: key, Value: tags[key]})
+	}
+	return tagging
+}
@@ -79,3 +79,15 @@ func isMinMultipartObjectSize(size string) bool {
 	}
 	return false
 }
+
+// isETagMatch - verify if the object md5 is one of the comma separated, optionally quoted, etags
+// in an If-Match style header, "*" matches any object
+func isETagMatch(etags, md5 string) bool {
+	for _, etag := range strings.Split(etags, ",") {
+		etag = strings.Trim(strings.TrimSpace(etag), "\"")
+		if etag == "*" || etag == md5 {
+			return true
+		}
+	}
+	return false
+}
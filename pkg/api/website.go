@@ -0,0 +1,338 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strings"
+
+	router "github.com/gorilla/mux"
+	"github.com/minio/minio/pkg/api/logging"
+	"github.com/minio/minio/pkg/api/quota"
+	"github.com/minio/minio/pkg/iodine"
+	"github.com/minio/minio/pkg/storage/drivers"
+	"github.com/minio/minio/pkg/utils/log"
+)
+
+// maximum size of a website configuration document
+const maxWebsiteConfigurationSize = 64 * 1024
+
+// maximum number of routing rules in a website configuration
+const maxWebsiteRoutingRules = 50
+
+// bucket resource name the website configuration is kept under
+const bucketWebsiteResource = "website"
+
+var errMalformedWebsiteConfiguration = errors.New("Malformed website configuration")
+
+// parseWebsiteConfiguration - decode and validate a website configuration document
+//
+//	<WebsiteConfiguration>
+//	  <IndexDocument><Suffix>index.html</Suffix></IndexDocument>
+//	  <ErrorDocument><Key>error.html</Key></ErrorDocument>
+//	  <RoutingRules>
+//	    <RoutingRule>
+//	      <Condition><KeyPrefixEquals>docs/</KeyPrefixEquals></Condition>
+//	      <Redirect><ReplaceKeyPrefixWith>documents/</ReplaceKeyPrefixWith></Redirect>
+//	    </RoutingRule>
+//	  </RoutingRules>
+//	</WebsiteConfiguration>
+//
+// or, redirecting every request to another host
+//
+//	<WebsiteConfiguration>
+//	  <RedirectAllRequestsTo><HostName>example.com</HostName></RedirectAllRequestsTo>
+//	</WebsiteConfiguration>
+func parseWebsiteConfiguration(data []byte) (*WebsiteConfiguration, error) {
+	config := new(WebsiteConfiguration)
+	if err := xml.Unmarshal(data, config); err != nil {
+		return nil, errMalformedWebsiteConfiguration
+	}
+	if config.RedirectAllRequestsTo != nil {
+		redirect := config.RedirectAllRequestsTo
+		if config.IndexDocument != nil || config.ErrorDocument != nil || len(config.RoutingRules) > 0 {
+			return nil, errMalformedWebsiteConfiguration
+		}
+		if redirect.HostName == "" || !isValidWebsiteProtocol(redirect.Protocol) {
+			return nil, errMalformedWebsiteConfiguration
+		}
+		return config, nil
+	}
+	if config.IndexDocument == nil || config.IndexDocument.Suffix == "" || strings.Contains(config.IndexDocument.Suffix, "/") {
+		return nil, errMalformedWebsiteConfiguration
+	}
+	if config.ErrorDocument != nil && config.ErrorDocument.Key == "" {
+		return nil, errMalformedWebsiteConfiguration
+	}
+	if len(config.RoutingRules) > maxWebsiteRoutingRules {
+		return nil, errMalformedWebsiteConfiguration
+	}
+	for _, rule := range config.RoutingRules {
+		if condition := rule.Condition; condition != nil {
+			if condition.KeyPrefixEquals == "" && condition.HTTPErrorCodeReturnedEquals == 0 {
+				return nil, errMalformedWebsiteConfiguration
+			}
+			if condition.HTTPErrorCodeReturnedEquals != 0 &&
+				(condition.HTTPErrorCodeReturnedEquals < 400 || condition.HTTPErrorCodeReturnedEquals > 599) {
+				return nil, errMalformedWebsiteConfiguration
+			}
+		}
+		redirect := rule.Redirect
+		if !isValidWebsiteProtocol(redirect.Protocol) {
+			return nil, errMalformedWebsiteConfiguration
+		}
+		if redirect.HTTPRedirectCode != 0 && (redirect.HTTPRedirectCode < 300 || redirect.HTTPRedirectCode > 399) {
+			return nil, errMalformedWebsiteConfiguration
+		}
+		if redirect.ReplaceKeyWith != "" && redirect.ReplaceKeyPrefixWith != nil {
+			return nil, errMalformedWebsiteConfiguration
+		}
+	}
+	return config, nil
+}
+
+// isValidWebsiteProtocol - redirects go to http or https, by default the protocol of the request
+func isValidWebsiteProtocol(protocol string) bool {
+	return protocol == "" || protocol == "http" || protocol == "https"
+}
+
+// getBucketWebsiteConfiguration - stored website configuration of a bucket
+func getBucketWebsiteConfiguration(driver drivers.Driver, bucket string) (*WebsiteConfiguration, error) {
+	data, err := driver.GetBucketResource(bucket, bucketWebsiteResource)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	config, err := parseWebsiteConfiguration(data)
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	return config, nil
+}
+
+// getRoutingRule - first routing rule matching a key, and the error code of the request when it failed
+func (c WebsiteConfiguration) getRoutingRule(key string, errorCode int) *RoutingRule {
+	for i, rule := range c.RoutingRules {
+		condition := rule.Condition
+		if condition == nil {
+			return &c.RoutingRules[i]
+		}
+		if !strings.HasPrefix(key, condition.KeyPrefixEquals) {
+			continue
+		}
+		if condition.HTTPErrorCodeReturnedEquals != errorCode {
+			continue
+		}
+		return &c.RoutingRules[i]
+	}
+	return nil
+}
+
+// getRedirectLocation - target of a routing rule, on the website of the bucket unless another host is given
+func (r RoutingRule) getRedirectLocation(req *http.Request, bucket, key string) string {
+	redirect := r.Redirect
+	switch {
+	case redirect.ReplaceKeyWith != "":
+		key = redirect.ReplaceKeyWith
+	case redirect.ReplaceKeyPrefixWith != nil:
+		if r.Condition != nil {
+			key = strings.TrimPrefix(key, r.Condition.KeyPrefixEquals)
+		}
+		key = *redirect.ReplaceKeyPrefixWith + key
+	}
+	if redirect.HostName == "" {
+		return getWebsiteProtocol(req, redirect.Protocol) + "://" + req.Host + "/" + bucket + "/" + key
+	}
+	return getWebsiteProtocol(req, redirect.Protocol) + "://" + redirect.HostName + "/" + key
+}
+
+// getRedirectCode - status of a routing rule redirect, moved permanently by default
+func (r RoutingRule) getRedirectCode() int {
+	if r.Redirect.HTTPRedirectCode != 0 {
+		return r.Redirect.HTTPRedirectCode
+	}
+	return http.StatusMovedPermanently
+}
+
+// getWebsiteProtocol - protocol of a redirect, the protocol of the request unless one is configured
+func getWebsiteProtocol(req *http.Request, protocol string) string {
+	if protocol != "" {
+		return protocol
+	}
+	if req.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// WebsiteHTTPHandler - http wrapper handler serving buckets as static websites, requests are anonymous
+// and only buckets which are readable by everyone are served
+func WebsiteHTTPHandler(config Config) http.Handler {
+	var mux *router.Router
+	var api = minioAPI{}
+	api.driver = config.GetDriver()
+
+	mux = router.NewRouter()
+	mux.HandleFunc("/{bucket}", api.websiteHandler).Methods("GET", "HEAD")
+	mux.HandleFunc("/{bucket}/{object:.*}", api.websiteHandler).Methods("GET", "HEAD")
+
+	var handler http.Handler = mux
+	handler = quota.RateLimit(handler, config.RateLimit)
+	handler = logging.LogHandler(handler)
+	return handler
+}
+
+// GET Website
+// -----------
+// This implementation of the GET operation serves the objects of a bucket as a static website. Requests
+// for the root of the bucket or for keys ending in "/" are served the index document of the directory,
+// and keys which do not exist are answered with the error document.
+func (server *minioAPI) websiteHandler(w http.ResponseWriter, req *http.Request) {
+	acceptsContentType := getContentType(req)
+
+	vars := router.Vars(req)
+	bucket := vars["bucket"]
+	object := vars["object"]
+
+	config, err := getBucketWebsiteConfiguration(server.driver, bucket)
+	switch iodine.ToError(err).(type) {
+	case nil:
+	case drivers.BucketResourceNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchWebsiteConfiguration, acceptsContentType, req.URL.Path)
+			return
+		}
+	case drivers.BucketNameInvalid:
+		{
+			writeErrorResponse(w, req, InvalidBucketName, acceptsContentType, req.URL.Path)
+			return
+		}
+	case drivers.BucketNotFound:
+		{
+			writeErrorResponse(w, req, NoSuchBucket, acceptsContentType, req.URL.Path)
+			return
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+			return
+		}
+	}
+
+	if redirect := config.RedirectAllRequestsTo; redirect != nil {
+		location := getWebsiteProtocol(req, redirect.Protocol) + "://" + redirect.HostName + "/" + object
+		http.Redirect(w, req, location, http.StatusMovedPermanently)
+		return
+	}
+
+	key := object
+	if key == "" || strings.HasSuffix(key, "/") {
+		key = key + config.IndexDocument.Suffix
+	}
+	if !server.isWebsiteReadable(w, req, bucket, key, acceptsContentType) {
+		return
+	}
+	if rule := config.getRoutingRule(key, 0); rule != nil {
+		http.Redirect(w, req, rule.getRedirectLocation(req, bucket, key), rule.getRedirectCode())
+		return
+	}
+
+	metadata, err := server.driver.GetObjectMetadata(bucket, key)
+	switch iodine.ToError(err).(type) {
+	case nil:
+		{
+			server.writeWebsiteObject(w, req, metadata, http.StatusOK)
+		}
+	case drivers.ObjectNotFound, drivers.ObjectNameInvalid:
+		{
+			// a directory given without the trailing "/" is redirected to it when it has an index document
+			if key == object {
+				if _, err := server.driver.GetObjectMetadata(bucket, key+"/"+config.IndexDocument.Suffix); err == nil {
+					http.Redirect(w, req, req.URL.Path+"/", http.StatusFound)
+					return
+				}
+			}
+			if rule := config.getRoutingRule(key, http.StatusNotFound); rule != nil {
+				http.Redirect(w, req, rule.getRedirectLocation(req, bucket, key), rule.getRedirectCode())
+				return
+			}
+			// the error document may not be readable by everyone when only some objects are
+			if config.ErrorDocument != nil {
+				errorKey := config.ErrorDocument.Key
+				if readable, err := server.isReadableByEveryone(req, bucket, errorKey); err == nil && readable {
+					if metadata, err := server.driver.GetObjectMetadata(bucket, errorKey); err == nil {
+						server.writeWebsiteObject(w, req, metadata, http.StatusNotFound)
+						return
+					}
+				}
+			}
+			writeErrorResponse(w, req, NoSuchKey, acceptsContentType, req.URL.Path)
+		}
+	default:
+		{
+			log.Error.Println(iodine.New(err, nil))
+			writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		}
+	}
+}
+
+// isWebsiteReadable - website requests are anonymous, the object must be readable by everyone through
+// the acls or the bucket policy
+func (server *minioAPI) isWebsiteReadable(w http.ResponseWriter, req *http.Request, bucket, key string, acceptsContentType contentType) bool {
+	readable, err := server.isReadableByEveryone(req, bucket, key)
+	if err != nil {
+		log.Error.Println(iodine.New(err, nil))
+		writeErrorResponse(w, req, InternalError, acceptsContentType, req.URL.Path)
+		return false
+	}
+	if !readable {
+		writeErrorResponse(w, req, AccessDenied, acceptsContentType, req.URL.Path)
+		return false
+	}
+	return true
+}
+
+// writeWebsiteObject - write an object of a website with the given status, without body for HEAD requests
+func (server *minioAPI) writeWebsiteObject(w http.ResponseWriter, req *http.Request, metadata drivers.ObjectMetadata, status int) {
+	if status == http.StatusOK {
+		switch evaluatePreconditions(req.Header, "", metadata) {
+		case preconditionFailed:
+			if req.Method == "HEAD" {
+				error := getErrorCode(PreconditionFailed)
+				w.Header().Set("Server", "Minio")
+				w.WriteHeader(error.HTTPStatusCode)
+				return
+			}
+			writeErrorResponse(w, req, PreconditionFailed, getContentType(req), req.URL.Path)
+			return
+		case preconditionNotModified:
+			writeNotModifiedResponse(w, metadata)
+			return
+		}
+	}
+	setObjectHeaders(w, metadata)
+	w.WriteHeader(status)
+	if req.Method == "HEAD" {
+		return
+	}
+	if _, err := server.driver.GetObject(w, metadata.Bucket, metadata.Key); err != nil {
+		// unable to write headers, we've already printed data. Just close the connection.
+		log.Error.Println(iodine.New(err, nil))
+	}
+}
@@ -30,6 +30,7 @@ type Config struct {
 	CertFile  string
 	KeyFile   string
 	RateLimit int
+	Domain    string
 }
 
 // Server - http server related
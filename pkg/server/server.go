@@ -19,52 +19,139 @@ package server
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/minio/minio/pkg/api"
+	"github.com/minio/minio/pkg/api/config"
 	"github.com/minio/minio/pkg/api/web"
 	"github.com/minio/minio/pkg/iodine"
 	"github.com/minio/minio/pkg/server/httpserver"
+	"github.com/minio/minio/pkg/storage/drivers"
 	"github.com/minio/minio/pkg/storage/drivers/donut"
 	fs "github.com/minio/minio/pkg/storage/drivers/fs"
 	"github.com/minio/minio/pkg/storage/drivers/memory"
 	"github.com/minio/minio/pkg/utils/log"
 )
 
+// interval at which the lifecycle rules of all buckets are applied
+const lifecycleInterval = time.Hour
+
+// interval at which the access logs of buckets are written into their target buckets
+const accessLogInterval = 5 * time.Minute
+
 // MemoryFactory is used to build memory api server
 type MemoryFactory struct {
 	httpserver.Config
-	MaxMemory  uint64
-	Expiration time.Duration
+	MaxMemory      uint64
+	Expiration     time.Duration
+	WebsiteAddress string
 }
 
 // GetStartServerFunc builds memory api server
 func (f MemoryFactory) GetStartServerFunc() StartServerFunc {
 	return func() (chan<- string, <-chan error) {
 		_, _, driver := memory.Start(f.MaxMemory, f.Expiration)
-		conf := api.Config{RateLimit: f.RateLimit}
-		conf.SetDriver(driver)
-		ctrl, status, _ := httpserver.Start(api.HTTPHandler(conf), f.Config)
-		return ctrl, status
+		return startAPIServer(driver, f.Config, f.WebsiteAddress)
 	}
 }
 
 // FilesystemFactory is used to build filesystem api server
 type FilesystemFactory struct {
 	httpserver.Config
-	Path string
+	Path           string
+	WebsiteAddress string
 }
 
 // GetStartServerFunc builds memory api server
 func (f FilesystemFactory) GetStartServerFunc() StartServerFunc {
 	return func() (chan<- string, <-chan error) {
 		_, _, driver := fs.Start(f.Path)
-		conf := api.Config{RateLimit: f.RateLimit}
-		conf.SetDriver(driver)
-		ctrl, status, _ := httpserver.Start(api.HTTPHandler(conf), f.Config)
-		return ctrl, status
+		return startAPIServer(driver, f.Config, f.WebsiteAddress)
+	}
+}
+
+// startAPIServer - start the api server for a driver, along with the website endpoint when
+// websiteAddress is set
+func startAPIServer(driver drivers.Driver, serverConfig httpserver.Config, websiteAddress string) (chan<- string, <-chan error) {
+	conf := api.Config{RateLimit: serverConfig.RateLimit, Domain: serverConfig.Domain}
+	conf.SetDriver(driver)
+	err := conf.LoadUsers()
+	if err == nil {
+		var queuePath string
+		queuePath, err = getNotificationQueuePath()
+		if err == nil {
+			err = conf.SetNotificationQueue(queuePath)
+		}
+	}
+	if err != nil {
+		log.Fatal(iodine.New(err, nil))
+	}
+	conf.SetAccessLogInterval(accessLogInterval)
+	lifecycleStop := api.StartLifecycle(driver, lifecycleInterval)
+	// the background workers stop along with the servers
+	stop := func() {
+		close(lifecycleStop)
+		conf.Stop()
+	}
+	ctrl, status, _ := httpserver.Start(api.HTTPHandler(conf), serverConfig)
+	if websiteAddress == "" {
+		return mergeControlChannels(stop, ctrl), status
+	}
+	websiteConfig := serverConfig
+	websiteConfig.Address = websiteAddress
+	websiteCtrl, websiteStatus, _ := httpserver.Start(api.WebsiteHTTPHandler(conf), websiteConfig)
+	return mergeControlChannels(stop, ctrl, websiteCtrl), mergeStatusChannels(status, websiteStatus)
+}
+
+// getNotificationQueuePath - bucket events waiting for delivery are queued in the config directory
+func getNotificationQueuePath() (string, error) {
+	conf := config.Config{}
+	if err := conf.SetupConfig(); err != nil {
+		return "", iodine.New(err, nil)
+	}
+	return filepath.Join(conf.GetConfigPath(), "notifications"), nil
+}
+
+// mergeControlChannels - a control channel which closes all the given channels when closed, and then
+// calls stop
+func mergeControlChannels(stop func(), channels ...chan<- string) chan<- string {
+	merged := make(chan string)
+	go func() {
+		for msg := range merged {
+			for _, ch := range channels {
+				ch <- msg
+			}
+		}
+		for _, ch := range channels {
+			close(ch)
+		}
+		stop()
+	}()
+	return merged
+}
+
+// mergeStatusChannels - a status channel receiving the errors of all the given channels, closed
+// once all of them are closed
+func mergeStatusChannels(channels ...<-chan error) <-chan error {
+	merged := make(chan error)
+	var wg sync.WaitGroup
+	wg.Add(len(channels))
+	for _, ch := range channels {
+		go func(ch <-chan error) {
+			defer wg.Done()
+			for err := range ch {
+				merged <- err
+			}
+		}(ch)
 	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged
 }
 
 // WebFactory is used to build web cli server
@@ -83,17 +170,15 @@ func (f WebFactory) GetStartServerFunc() StartServerFunc {
 // DonutFactory is used to build donut api server
 type DonutFactory struct {
 	httpserver.Config
-	Paths []string
+	Paths          []string
+	WebsiteAddress string
 }
 
 // GetStartServerFunc DonutFactory builds donut api server
 func (f DonutFactory) GetStartServerFunc() StartServerFunc {
 	return func() (chan<- string, <-chan error) {
 		_, _, driver := donut.Start(f.Paths)
-		conf := api.Config{RateLimit: f.RateLimit}
-		conf.SetDriver(driver)
-		ctrl, status, _ := httpserver.Start(api.HTTPHandler(conf), f.Config)
-		return ctrl, status
+		return startAPIServer(driver, f.Config, f.WebsiteAddress)
 	}
 }
 
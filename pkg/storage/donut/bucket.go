@@ -23,6 +23,7 @@ import (
 	"io"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -85,10 +86,11 @@ func (b bucket) getBucketName() string {
 	return b.name
 }
 
-func (b bucket) GetObjectMetadata(objectName string) (ObjectMetadata, error) {
+// GetObjectMetadata - read the metadata of a version of an object
+func (b bucket) GetObjectMetadata(objectName, versionID string) (ObjectMetadata, error) {
 	b.lock.RLock()
 	defer b.lock.RUnlock()
-	metadataReaders, err := b.getDiskReaders(normalizeObjectName(objectName), objectMetadataConfig)
+	metadataReaders, err := b.getDiskReaders(normalizeObjectName(objectVersionName(objectName, versionID)), objectMetadataConfig)
 	if err != nil {
 		return ObjectMetadata{}, iodine.New(err, nil)
 	}
@@ -106,6 +108,44 @@ func (b bucket) GetObjectMetadata(objectName string) (ObjectMetadata, error) {
 	return ObjectMetadata{}, iodine.New(InvalidArgument{}, nil)
 }
 
+// SetObjectTags - replace the tags kept in the metadata of a version of an object
+func (b bucket) SetObjectTags(objectName, versionID string, tags map[string]string) error {
+	return b.updateObjectMetadata(objectName, versionID, func(objMetadata *ObjectMetadata) {
+		objMetadata.Tags = tags
+	})
+}
+
+// SetObjectACL - replace the ACL grants kept in the metadata of a version of an object
+func (b bucket) SetObjectACL(objectName, versionID string, grants []Grant) error {
+	return b.updateObjectMetadata(objectName, versionID, func(objMetadata *ObjectMetadata) {
+		objMetadata.ACL = grants
+	})
+}
+
+// ReplaceObjectMetadata - replace the metadata and grants of a version of an object in place, its data is kept
+func (b bucket) ReplaceObjectMetadata(objectName, versionID string, metadata map[string]string, grants []Grant) error {
+	return b.updateObjectMetadata(objectName, versionID, func(objMetadata *ObjectMetadata) {
+		objMetadata.Created = time.Now().UTC()
+		objMetadata.Metadata = metadata
+		objMetadata.ACL = grants
+	})
+}
+
+// updateObjectMetadata - rewrite the metadata of a version of an object after applying update to it
+func (b bucket) updateObjectMetadata(objectName, versionID string, update func(*ObjectMetadata)) error {
+	objMetadata, err := b.GetObjectMetadata(objectName, versionID)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	update(&objMetadata)
+	if err := b.writeObjectMetadata(normalizeObjectName(objectVersionName(objectName, versionID)), &objMetadata); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
 func (b bucket) getBucketMetadataReaders() ([]io.ReadCloser, error) {
 	var readers []io.ReadCloser
 	for _, node := range b.nodes {
@@ -196,20 +236,12 @@ func (b bucket) ListObjects(prefix, marker, delimiter string, maxkeys int) ([]st
 	return results, commonPrefixes, isTruncated, nil
 }
 
-// ReadObject - open an object to read
-func (b bucket) ReadObject(objectName string) (reader io.ReadCloser, size int64, err error) {
+// ReadObject - open a version of an object to read, callers verify the version exists
+func (b bucket) ReadObject(objectName, versionID string) (reader io.ReadCloser, size int64, err error) {
 	b.lock.RLock()
 	defer b.lock.RUnlock()
 	reader, writer := io.Pipe()
-	// get list of objects
-	bucketMetadata, err := b.getBucketMetadata()
-	if err != nil {
-		return nil, 0, iodine.New(err, nil)
-	}
-	// check if object exists
-	if _, ok := bucketMetadata.Buckets[b.getBucketName()].BucketObjects[objectName]; !ok {
-		return nil, 0, iodine.New(ObjectNotFound{Object: objectName}, nil)
-	}
+	objectName = objectVersionName(objectName, versionID)
 	objMetadata := ObjectMetadata{}
 	metadataReaders, err := b.getDiskReaders(normalizeObjectName(objectName), objectMetadataConfig)
 	if err != nil {
@@ -230,16 +262,16 @@ func (b bucket) ReadObject(objectName string) (reader io.ReadCloser, size int64,
 	return reader, objMetadata.Size, nil
 }
 
-// WriteObject - write a new object into bucket
-func (b bucket) WriteObject(objectName string, objectData io.Reader, expectedMD5Sum string, metadata map[string]string) (string, error) {
+// WriteObject - write a new version of an object into bucket, its tags and grants are written along with its metadata
+func (b bucket) WriteObject(objectName, versionID string, objectData io.Reader, expectedMD5Sum string, metadata, tags map[string]string, grants []Grant) (ObjectMetadata, error) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 	if objectName == "" || objectData == nil {
-		return "", iodine.New(InvalidArgument{}, nil)
+		return ObjectMetadata{}, iodine.New(InvalidArgument{}, nil)
 	}
-	writers, err := b.getDiskWriters(normalizeObjectName(objectName), "data")
+	writers, err := b.getDiskWriters(normalizeObjectName(objectVersionName(objectName, versionID)), "data")
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return ObjectMetadata{}, iodine.New(err, nil)
 	}
 	sumMD5 := md5.New()
 	sum512 := sha512.New()
@@ -252,19 +284,19 @@ func (b bucket) WriteObject(objectName string, objectData io.Reader, expectedMD5
 		mw := io.MultiWriter(writers[0], sumMD5, sum512)
 		totalLength, err := io.Copy(mw, objectData)
 		if err != nil {
-			return "", iodine.New(err, nil)
+			return ObjectMetadata{}, iodine.New(err, nil)
 		}
 		objMetadata.Size = totalLength
 	case false:
 		// calculate data and parity dictated by total number of writers
 		k, m, err := b.getDataAndParity(len(writers))
 		if err != nil {
-			return "", iodine.New(err, nil)
+			return ObjectMetadata{}, iodine.New(err, nil)
 		}
 		// encoded data with k, m and write
 		chunkCount, totalLength, err := b.writeEncodedData(k, m, writers, objectData, sumMD5, sum512)
 		if err != nil {
-			return "", iodine.New(err, nil)
+			return ObjectMetadata{}, iodine.New(err, nil)
 		}
 		/// donutMetadata section
 		objMetadata.BlockSize = blockSize
@@ -274,8 +306,13 @@ func (b bucket) WriteObject(objectName string, objectData io.Reader, expectedMD5
 		objMetadata.ErasureTechnique = "Cauchy"
 		objMetadata.Size = int64(totalLength)
 	}
+	// a short read of the object data never passes for a complete object
+	if contentLength, ok := metadata["contentLength"]; ok && contentLength != strconv.FormatInt(objMetadata.Size, 10) {
+		return ObjectMetadata{}, iodine.New(io.ErrUnexpectedEOF, nil)
+	}
 	objMetadata.Bucket = b.getBucketName()
 	objMetadata.Object = objectName
+	objMetadata.VersionID = versionID
 	dataMD5sum := sumMD5.Sum(nil)
 	dataSHA512sum := sum512.Sum(nil)
 
@@ -285,20 +322,82 @@ func (b bucket) WriteObject(objectName string, objectData io.Reader, expectedMD5
 	// Verify if the written object is equal to what is expected, only if it is requested as such
 	if strings.TrimSpace(expectedMD5Sum) != "" {
 		if err := b.isMD5SumEqual(strings.TrimSpace(expectedMD5Sum), objMetadata.MD5Sum); err != nil {
-			return "", iodine.New(err, nil)
+			return ObjectMetadata{}, iodine.New(err, nil)
 		}
 	}
 
 	objMetadata.Metadata = metadata
+	objMetadata.Tags = tags
+	objMetadata.ACL = grants
 	// write object specific metadata
-	if err := b.writeObjectMetadata(normalizeObjectName(objectName), objMetadata); err != nil {
-		return "", iodine.New(err, nil)
+	if err := b.writeObjectMetadata(normalizeObjectName(objectVersionName(objectName, versionID)), objMetadata); err != nil {
+		return ObjectMetadata{}, iodine.New(err, nil)
 	}
 	// close all writers, when control flow reaches here
 	for _, writer := range writers {
 		writer.Close()
 	}
-	return objMetadata.MD5Sum, nil
+	return *objMetadata, nil
+}
+
+// DeleteObject - remove the data and metadata of a version of an object from every disk
+func (b bucket) DeleteObject(objectName, versionID string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if objectName == "" {
+		return iodine.New(InvalidArgument{}, nil)
+	}
+	nodeSlice := 0
+	for _, node := range b.nodes {
+		disks, err := node.ListDisks()
+		if err != nil {
+			return iodine.New(err, nil)
+		}
+		for order, disk := range disks {
+			bucketSlice := fmt.Sprintf("%s$%d$%d", b.name, nodeSlice, order)
+			objectPath := filepath.Join(b.donutName, bucketSlice, normalizeObjectName(objectVersionName(objectName, versionID)))
+			if err := disk.RemoveDir(objectPath); err != nil {
+				return iodine.New(err, nil)
+			}
+		}
+		nodeSlice = nodeSlice + 1
+	}
+	return nil
+}
+
+// RenameObject - move the data and metadata of a version of an object to another version id on every disk
+func (b bucket) RenameObject(objectName, versionID, newVersionID string) error {
+	if err := b.renameObject(objectName, versionID, newVersionID); err != nil {
+		return iodine.New(err, nil)
+	}
+	return b.updateObjectMetadata(objectName, newVersionID, func(objMetadata *ObjectMetadata) {
+		objMetadata.VersionID = newVersionID
+	})
+}
+
+func (b bucket) renameObject(objectName, versionID, newVersionID string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if objectName == "" {
+		return iodine.New(InvalidArgument{}, nil)
+	}
+	nodeSlice := 0
+	for _, node := range b.nodes {
+		disks, err := node.ListDisks()
+		if err != nil {
+			return iodine.New(err, nil)
+		}
+		for order, disk := range disks {
+			bucketSlice := fmt.Sprintf("%s$%d$%d", b.name, nodeSlice, order)
+			objectPath := filepath.Join(b.donutName, bucketSlice, normalizeObjectName(objectVersionName(objectName, versionID)))
+			newObjectPath := filepath.Join(b.donutName, bucketSlice, normalizeObjectName(objectVersionName(objectName, newVersionID)))
+			if err := disk.Rename(objectPath, newObjectPath); err != nil {
+				return iodine.New(err, nil)
+			}
+		}
+		nodeSlice = nodeSlice + 1
+	}
+	return nil
 }
 
 // isMD5SumEqual - returns error if md5sum mismatches, other its `nil`
@@ -380,16 +479,17 @@ func (b bucket) writeEncodedData(k, m uint8, writers []io.WriteCloser, objectDat
 	chunkCount := 0
 	totalLength := 0
 	for chunk := range chunks {
-		if chunk.Err == nil {
-			totalLength = totalLength + len(chunk.Data)
-			encodedBlocks, _ := encoder.Encode(chunk.Data)
-			sumMD5.Write(chunk.Data)
-			sum512.Write(chunk.Data)
-			for blockIndex, block := range encodedBlocks {
-				_, err := io.Copy(writers[blockIndex], bytes.NewBuffer(block))
-				if err != nil {
-					return 0, 0, iodine.New(err, nil)
-				}
+		if chunk.Err != nil {
+			return 0, 0, iodine.New(chunk.Err, nil)
+		}
+		totalLength = totalLength + len(chunk.Data)
+		encodedBlocks, _ := encoder.Encode(chunk.Data)
+		sumMD5.Write(chunk.Data)
+		sum512.Write(chunk.Data)
+		for blockIndex, block := range encodedBlocks {
+			_, err := io.Copy(writers[blockIndex], bytes.NewBuffer(block))
+			if err != nil {
+				return 0, 0, iodine.New(err, nil)
 			}
 		}
 		chunkCount = chunkCount + 1
@@ -42,6 +42,20 @@ type ObjectMetadata struct {
 
 	// metadata
 	Metadata map[string]string `json:"metadata"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	ACL      []Grant           `json:"acl,omitempty"`
+
+	// versioning, the version id is empty for the null version
+	VersionID    string `json:"versionId,omitempty"`
+	DeleteMarker bool   `json:"deleteMarker,omitempty"`
+	IsLatest     bool   `json:"-"`
+}
+
+// Grant container for a permission of an object ACL, the grantee is either a user id or a group uri
+type Grant struct {
+	GranteeID  string `json:"granteeId,omitempty"`
+	GranteeURI string `json:"granteeUri,omitempty"`
+	Permission string `json:"permission"`
 }
 
 // Metadata container for donut metadata
@@ -63,4 +77,15 @@ type BucketMetadata struct {
 	Created       time.Time              `json:"created"`
 	Metadata      map[string]string      `json:"metadata"`
 	BucketObjects map[string]interface{} `json:"objects"`
+
+	// versioning status, and the earlier versions and delete markers of every object newest first
+	Versioning     string                     `json:"versioning,omitempty"`
+	ObjectVersions map[string][]ObjectVersion `json:"versions,omitempty"`
+}
+
+// ObjectVersion container for an earlier version of an object or a delete marker
+type ObjectVersion struct {
+	VersionID    string    `json:"versionId"`
+	DeleteMarker bool      `json:"deleteMarker,omitempty"`
+	Created      time.Time `json:"created"`
 }
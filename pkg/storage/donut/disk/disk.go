@@ -87,6 +87,28 @@ func (disk Disk) MakeDir(dirname string) error {
 	return os.MkdirAll(filepath.Join(disk.path, dirname), 0700)
 }
 
+// RemoveDir - remove a directory and all its contents inside disk root path
+func (disk Disk) RemoveDir(dirname string) error {
+	if dirname == "" {
+		return iodine.New(InvalidArgument{}, nil)
+	}
+	if err := os.RemoveAll(filepath.Join(disk.path, dirname)); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// Rename - rename a file or directory inside disk root path
+func (disk Disk) Rename(oldname, newname string) error {
+	if oldname == "" || newname == "" {
+		return iodine.New(InvalidArgument{}, nil)
+	}
+	if err := os.Rename(filepath.Join(disk.path, oldname), filepath.Join(disk.path, newname)); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
 // ListDir - list a directory inside disk root path, get only directories
 func (disk Disk) ListDir(dirname string) ([]os.FileInfo, error) {
 	dir, err := os.Open(filepath.Join(disk.path, dirname))
@@ -139,7 +161,7 @@ func (disk Disk) CreateFile(filename string) (*os.File, error) {
 	if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
 		return nil, iodine.New(err, nil)
 	}
-	dataFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE, 0600)
+	dataFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return nil, iodine.New(err, nil)
 	}
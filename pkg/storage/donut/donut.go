@@ -95,6 +95,16 @@ func (dt donut) MakeBucket(bucket, acl string) error {
 	return dt.makeDonutBucket(bucket, acl)
 }
 
+// DeleteBucket - delete an empty bucket
+func (dt donut) DeleteBucket(bucket string) error {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+	if bucket == "" || strings.TrimSpace(bucket) == "" {
+		return iodine.New(InvalidArgument{}, nil)
+	}
+	return dt.deleteDonutBucket(bucket)
+}
+
 // GetBucketMetadata - get bucket metadata
 func (dt donut) GetBucketMetadata(bucketName string) (BucketMetadata, error) {
 	dt.lock.RLock()
@@ -123,12 +133,31 @@ func (dt donut) SetBucketMetadata(bucketName string, bucketMetadata map[string]s
 	if err != nil {
 		return iodine.New(err, nil)
 	}
-	oldBucketMetadata := metadata.Buckets[bucketName]
-	acl, ok := bucketMetadata["acl"]
-	if !ok {
+	if _, ok := dt.buckets[bucketName]; !ok {
+		return iodine.New(BucketNotFound{Bucket: bucketName}, nil)
+	}
+	if len(bucketMetadata) == 0 {
 		return iodine.New(InvalidArgument{}, nil)
 	}
-	oldBucketMetadata.ACL = acl
+	oldBucketMetadata := metadata.Buckets[bucketName]
+	for key, value := range bucketMetadata {
+		switch key {
+		case "acl":
+			oldBucketMetadata.ACL = value
+		case "versioning":
+			oldBucketMetadata.Versioning = value
+		default:
+			// any other key is kept in the free form metadata, an empty value removes it
+			if oldBucketMetadata.Metadata == nil {
+				oldBucketMetadata.Metadata = make(map[string]string)
+			}
+			if value == "" {
+				delete(oldBucketMetadata.Metadata, key)
+				continue
+			}
+			oldBucketMetadata.Metadata[key] = value
+		}
+	}
 	metadata.Buckets[bucketName] = oldBucketMetadata
 	return dt.setDonutBucketMetadata(metadata)
 }
@@ -175,7 +204,7 @@ func (dt donut) ListObjects(bucket, prefix, marker, delimiter string, maxkeys in
 }
 
 // PutObject - put object
-func (dt donut) PutObject(bucket, object, expectedMD5Sum string, reader io.ReadCloser, metadata map[string]string) (string, error) {
+func (dt donut) PutObject(bucket, object, expectedMD5Sum string, reader io.ReadCloser, metadata, tags map[string]string, grants []Grant) (ObjectMetadata, error) {
 	dt.lock.Lock()
 	defer dt.lock.Unlock()
 	errParams := map[string]string{
@@ -183,33 +212,59 @@ func (dt donut) PutObject(bucket, object, expectedMD5Sum string, reader io.ReadC
 		"object": object,
 	}
 	if bucket == "" || strings.TrimSpace(bucket) == "" {
-		return "", iodine.New(InvalidArgument{}, errParams)
+		return ObjectMetadata{}, iodine.New(InvalidArgument{}, errParams)
 	}
 	if object == "" || strings.TrimSpace(object) == "" {
-		return "", iodine.New(InvalidArgument{}, errParams)
+		return ObjectMetadata{}, iodine.New(InvalidArgument{}, errParams)
 	}
 	if err := dt.listDonutBuckets(); err != nil {
-		return "", iodine.New(err, errParams)
+		return ObjectMetadata{}, iodine.New(err, errParams)
 	}
 	if _, ok := dt.buckets[bucket]; !ok {
-		return "", iodine.New(BucketNotFound{Bucket: bucket}, nil)
+		return ObjectMetadata{}, iodine.New(BucketNotFound{Bucket: bucket}, nil)
 	}
 	bucketMeta, err := dt.getDonutBucketMetadata()
 	if err != nil {
-		return "", iodine.New(err, errParams)
+		return ObjectMetadata{}, iodine.New(err, errParams)
 	}
-	if _, ok := bucketMeta.Buckets[bucket].BucketObjects[object]; ok {
-		return "", iodine.New(ObjectExists{Object: object}, errParams)
+	// without versioning an existing object is never replaced, with versioning it becomes an earlier version
+	// once the new one is written, with versioning suspended the new null version is written aside until then
+	bucketMetadata := bucketMeta.Buckets[bucket]
+	versionID := ""
+	writeVersionID := ""
+	switch bucketMetadata.Versioning {
+	case "":
+		if _, ok := bucketMetadata.BucketObjects[object]; ok {
+			return ObjectMetadata{}, iodine.New(ObjectExists{Object: object}, errParams)
+		}
+	case versioningEnabled:
+		versionID = newVersionID(bucket, object)
+		writeVersionID = versionID
+	default:
+		writeVersionID = newVersionID(bucket, object)
 	}
-	md5sum, err := dt.buckets[bucket].WriteObject(object, reader, expectedMD5Sum, metadata)
+	objectMetadata, err := dt.buckets[bucket].WriteObject(object, writeVersionID, reader, expectedMD5Sum, metadata, tags, grants)
 	if err != nil {
-		return "", iodine.New(err, errParams)
+		return ObjectMetadata{}, iodine.New(err, errParams)
+	}
+	if bucketMetadata.Versioning != "" {
+		if err := dt.archiveObject(&bucketMetadata, bucket, object); err != nil {
+			return ObjectMetadata{}, iodine.New(err, errParams)
+		}
+	}
+	if writeVersionID != versionID {
+		if err := dt.buckets[bucket].RenameObject(object, writeVersionID, versionID); err != nil {
+			return ObjectMetadata{}, iodine.New(err, errParams)
+		}
+		objectMetadata.VersionID = versionID
 	}
-	bucketMeta.Buckets[bucket].BucketObjects[object] = 1
+	setCurrentVersion(&bucketMetadata, object, versionID)
+	bucketMeta.Buckets[bucket] = bucketMetadata
 	if err := dt.setDonutBucketMetadata(bucketMeta); err != nil {
-		return "", iodine.New(err, errParams)
+		return ObjectMetadata{}, iodine.New(err, errParams)
 	}
-	return md5sum, nil
+	objectMetadata.IsLatest = true
+	return objectMetadata, nil
 }
 
 // GetObject - get object
@@ -232,7 +287,15 @@ func (dt donut) GetObject(bucket, object string) (reader io.ReadCloser, size int
 	if _, ok := dt.buckets[bucket]; !ok {
 		return nil, 0, iodine.New(BucketNotFound{Bucket: bucket}, errParams)
 	}
-	return dt.buckets[bucket].ReadObject(object)
+	bucketMeta, err := dt.getDonutBucketMetadata()
+	if err != nil {
+		return nil, 0, iodine.New(err, errParams)
+	}
+	value, ok := bucketMeta.Buckets[bucket].BucketObjects[object]
+	if !ok {
+		return nil, 0, iodine.New(ObjectNotFound{Object: object}, errParams)
+	}
+	return dt.buckets[bucket].ReadObject(object, getVersionID(value))
 }
 
 // GetObjectMetadata - get object metadata
@@ -253,16 +316,163 @@ func (dt donut) GetObjectMetadata(bucket, object string) (ObjectMetadata, error)
 	if err != nil {
 		return ObjectMetadata{}, iodine.New(err, errParams)
 	}
-	if _, ok := bucketMeta.Buckets[bucket].BucketObjects[object]; !ok {
+	value, ok := bucketMeta.Buckets[bucket].BucketObjects[object]
+	if !ok {
 		return ObjectMetadata{}, iodine.New(ObjectNotFound{Object: object}, errParams)
 	}
-	objectMetadata, err := dt.buckets[bucket].GetObjectMetadata(object)
+	objectMetadata, err := dt.buckets[bucket].GetObjectMetadata(object, getVersionID(value))
 	if err != nil {
 		return ObjectMetadata{}, iodine.New(err, nil)
 	}
+	objectMetadata.IsLatest = true
 	return objectMetadata, nil
 }
 
+// SetObjectTags - replace the tags of the current version of an object
+func (dt donut) SetObjectTags(bucket, object string, tags map[string]string) error {
+	return dt.updateObjectMetadata(bucket, object, func(versionID string) error {
+		return dt.buckets[bucket].SetObjectTags(object, versionID, tags)
+	})
+}
+
+// SetObjectACL - replace the ACL grants of the current version of an object
+func (dt donut) SetObjectACL(bucket, object string, grants []Grant) error {
+	return dt.updateObjectMetadata(bucket, object, func(versionID string) error {
+		return dt.buckets[bucket].SetObjectACL(object, versionID, grants)
+	})
+}
+
+// ReplaceObjectMetadata - replace the metadata of the current version of an object in place, for an
+// object copied onto itself where writing it anew would overwrite the data being read
+func (dt donut) ReplaceObjectMetadata(bucket, object string, metadata map[string]string, grants []Grant) (ObjectMetadata, error) {
+	err := dt.updateObjectMetadata(bucket, object, func(versionID string) error {
+		return dt.buckets[bucket].ReplaceObjectMetadata(object, versionID, metadata, grants)
+	})
+	if err != nil {
+		return ObjectMetadata{}, iodine.New(err, nil)
+	}
+	return dt.GetObjectMetadata(bucket, object)
+}
+
+// updateObjectMetadata - apply update to the current version of an object, holding the donut lock
+func (dt donut) updateObjectMetadata(bucket, object string, update func(versionID string) error) error {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+	errParams := map[string]string{
+		"bucket": bucket,
+		"object": object,
+	}
+	if err := dt.listDonutBuckets(); err != nil {
+		return iodine.New(err, errParams)
+	}
+	if _, ok := dt.buckets[bucket]; !ok {
+		return iodine.New(BucketNotFound{Bucket: bucket}, errParams)
+	}
+	bucketMeta, err := dt.getDonutBucketMetadata()
+	if err != nil {
+		return iodine.New(err, errParams)
+	}
+	value, ok := bucketMeta.Buckets[bucket].BucketObjects[object]
+	if !ok {
+		return iodine.New(ObjectNotFound{Object: object}, errParams)
+	}
+	if err := update(getVersionID(value)); err != nil {
+		return iodine.New(err, errParams)
+	}
+	return nil
+}
+
+// DeleteObject - delete object
+func (dt donut) DeleteObject(bucket, object string) error {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+	errParams := map[string]string{
+		"bucket": bucket,
+		"object": object,
+	}
+	if bucket == "" || strings.TrimSpace(bucket) == "" {
+		return iodine.New(InvalidArgument{}, errParams)
+	}
+	if object == "" || strings.TrimSpace(object) == "" {
+		return iodine.New(InvalidArgument{}, errParams)
+	}
+	if err := dt.listDonutBuckets(); err != nil {
+		return iodine.New(err, errParams)
+	}
+	if _, ok := dt.buckets[bucket]; !ok {
+		return iodine.New(BucketNotFound{Bucket: bucket}, errParams)
+	}
+	bucketMeta, err := dt.getDonutBucketMetadata()
+	if err != nil {
+		return iodine.New(err, errParams)
+	}
+	if err := dt.deleteObject(bucketMeta, bucket, object); err != nil {
+		return iodine.New(err, errParams)
+	}
+	if err := dt.setDonutBucketMetadata(bucketMeta); err != nil {
+		return iodine.New(err, errParams)
+	}
+	return nil
+}
+
+// DeleteObjects - delete several objects of a bucket, bucket metadata is written once for all of them
+func (dt donut) DeleteObjects(bucket string, objects []string) (map[string]error, error) {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+	errParams := map[string]string{
+		"bucket": bucket,
+	}
+	if bucket == "" || strings.TrimSpace(bucket) == "" {
+		return nil, iodine.New(InvalidArgument{}, errParams)
+	}
+	if err := dt.listDonutBuckets(); err != nil {
+		return nil, iodine.New(err, errParams)
+	}
+	if _, ok := dt.buckets[bucket]; !ok {
+		return nil, iodine.New(BucketNotFound{Bucket: bucket}, errParams)
+	}
+	bucketMeta, err := dt.getDonutBucketMetadata()
+	if err != nil {
+		return nil, iodine.New(err, errParams)
+	}
+	errs := make(map[string]error)
+	for _, object := range objects {
+		if object == "" || strings.TrimSpace(object) == "" {
+			errs[object] = iodine.New(InvalidArgument{}, errParams)
+			continue
+		}
+		if err := dt.deleteObject(bucketMeta, bucket, object); err != nil {
+			errs[object] = iodine.New(err, errParams)
+		}
+	}
+	if err := dt.setDonutBucketMetadata(bucketMeta); err != nil {
+		return nil, iodine.New(err, errParams)
+	}
+	return errs, nil
+}
+
+// deleteObject - remove an object from the bucket metadata and its data from every disk, with versioning
+// a delete marker replaces the object instead, caller writes the bucket metadata
+func (dt donut) deleteObject(bucketMeta *AllBuckets, bucket, object string) error {
+	bucketMetadata := bucketMeta.Buckets[bucket]
+	if bucketMetadata.Versioning != "" {
+		if err := dt.createDeleteMarker(&bucketMetadata, bucket, object); err != nil {
+			return iodine.New(err, nil)
+		}
+		bucketMeta.Buckets[bucket] = bucketMetadata
+		return nil
+	}
+	value, ok := bucketMetadata.BucketObjects[object]
+	if !ok {
+		return iodine.New(ObjectNotFound{Object: object}, nil)
+	}
+	if err := dt.buckets[bucket].DeleteObject(object, getVersionID(value)); err != nil {
+		return iodine.New(err, nil)
+	}
+	delete(bucketMetadata.BucketObjects, object)
+	return nil
+}
+
 // getDiskWriters -
 func (dt donut) getBucketMetadataWriters() ([]io.WriteCloser, error) {
 	var writers []io.WriteCloser
@@ -388,6 +598,44 @@ func (dt donut) makeDonutBucket(bucketName, acl string) error {
 	return nil
 }
 
+func (dt donut) deleteDonutBucket(bucketName string) error {
+	if err := dt.listDonutBuckets(); err != nil {
+		return iodine.New(err, nil)
+	}
+	if _, ok := dt.buckets[bucketName]; !ok {
+		return iodine.New(BucketNotFound{Bucket: bucketName}, nil)
+	}
+	metadata, err := dt.getDonutBucketMetadata()
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	if len(metadata.Buckets[bucketName].BucketObjects) > 0 || len(metadata.Buckets[bucketName].ObjectVersions) > 0 {
+		return iodine.New(BucketNotEmpty{Bucket: bucketName}, nil)
+	}
+	nodeNumber := 0
+	for _, node := range dt.nodes {
+		disks, err := node.ListDisks()
+		if err != nil {
+			return iodine.New(err, nil)
+		}
+		for order, disk := range disks {
+			bucketSlice := fmt.Sprintf("%s$%d$%d", bucketName, nodeNumber, order)
+			err := disk.RemoveDir(filepath.Join(dt.name, bucketSlice))
+			if err != nil {
+				return iodine.New(err, nil)
+			}
+		}
+		nodeNumber = nodeNumber + 1
+	}
+	delete(dt.buckets, bucketName)
+	delete(metadata.Buckets, bucketName)
+	err = dt.setDonutBucketMetadata(metadata)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
 func (dt donut) listDonutBuckets() error {
 	for _, node := range dt.nodes {
 		disks, err := node.ListDisks()
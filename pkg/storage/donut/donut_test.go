@@ -167,7 +167,7 @@ func (s *MySuite) TestNewObjectFailsWithoutBucket(c *C) {
 	defer os.RemoveAll(root)
 	donut, err := NewDonut("test", createTestNodeDiskMap(root))
 	c.Assert(err, IsNil)
-	_, err = donut.PutObject("foo", "obj", "", nil, nil)
+	_, err = donut.PutObject("foo", "obj", "", nil, nil, nil, nil)
 	c.Assert(err, Not(IsNil))
 }
 
@@ -194,9 +194,9 @@ func (s *MySuite) TestNewObjectMetadata(c *C) {
 	err = donut.MakeBucket("foo", "private")
 	c.Assert(err, IsNil)
 
-	calculatedMd5Sum, err := donut.PutObject("foo", "obj", expectedMd5Sum, reader, metadata)
+	putMetadata, err := donut.PutObject("foo", "obj", expectedMd5Sum, reader, metadata, nil, nil)
 	c.Assert(err, IsNil)
-	c.Assert(calculatedMd5Sum, Equals, expectedMd5Sum)
+	c.Assert(putMetadata.MD5Sum, Equals, expectedMd5Sum)
 
 	objectMetadata, err := donut.GetObjectMetadata("foo", "obj")
 	c.Assert(err, IsNil)
@@ -214,7 +214,7 @@ func (s *MySuite) TestNewObjectFailsWithEmptyName(c *C) {
 	donut, err := NewDonut("test", createTestNodeDiskMap(root))
 	c.Assert(err, IsNil)
 
-	_, err = donut.PutObject("foo", "", "", nil, nil)
+	_, err = donut.PutObject("foo", "", "", nil, nil, nil, nil)
 	c.Assert(err, Not(IsNil))
 }
 
@@ -239,9 +239,9 @@ func (s *MySuite) TestNewObjectCanBeWritten(c *C) {
 	reader := ioutil.NopCloser(bytes.NewReader([]byte(data)))
 	metadata["contentLength"] = strconv.Itoa(len(data))
 
-	calculatedMd5Sum, err := donut.PutObject("foo", "obj", expectedMd5Sum, reader, metadata)
+	putMetadata, err := donut.PutObject("foo", "obj", expectedMd5Sum, reader, metadata, nil, nil)
 	c.Assert(err, IsNil)
-	c.Assert(calculatedMd5Sum, Equals, expectedMd5Sum)
+	c.Assert(putMetadata.MD5Sum, Equals, expectedMd5Sum)
 
 	reader, size, err := donut.GetObject("foo", "obj")
 	c.Assert(err, IsNil)
@@ -273,13 +273,13 @@ func (s *MySuite) TestMultipleNewObjects(c *C) {
 	metadata := make(map[string]string)
 	metadata["contentLength"] = strconv.Itoa(len("one"))
 
-	_, err = donut.PutObject("foo", "obj1", "", one, metadata)
+	_, err = donut.PutObject("foo", "obj1", "", one, metadata, nil, nil)
 	c.Assert(err, IsNil)
 
 	two := ioutil.NopCloser(bytes.NewReader([]byte("two")))
 
 	metadata["contentLength"] = strconv.Itoa(len("two"))
-	_, err = donut.PutObject("foo", "obj2", "", two, metadata)
+	_, err = donut.PutObject("foo", "obj2", "", two, metadata, nil, nil)
 	c.Assert(err, IsNil)
 
 	obj1, size, err := donut.GetObject("foo", "obj1")
@@ -323,7 +323,7 @@ func (s *MySuite) TestMultipleNewObjects(c *C) {
 
 	three := ioutil.NopCloser(bytes.NewReader([]byte("three")))
 	metadata["contentLength"] = strconv.Itoa(len("three"))
-	_, err = donut.PutObject("foo", "obj3", "", three, metadata)
+	_, err = donut.PutObject("foo", "obj3", "", three, metadata, nil, nil)
 	c.Assert(err, IsNil)
 
 	obj3, size, err := donut.GetObject("foo", "obj3")
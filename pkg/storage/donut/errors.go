@@ -41,6 +41,15 @@ func (e BucketNotFound) Error() string {
 	return "Bucket not found: " + e.Bucket
 }
 
+// BucketNotEmpty bucket still has objects
+type BucketNotEmpty struct {
+	Bucket string
+}
+
+func (e BucketNotEmpty) Error() string {
+	return "Bucket not empty: " + e.Bucket
+}
+
 // ObjectExists object exists
 type ObjectExists struct {
 	Object string
@@ -59,6 +68,16 @@ func (e ObjectNotFound) Error() string {
 	return "Object not found: " + e.Object
 }
 
+// VersionNotFound object version not found
+type VersionNotFound struct {
+	Object    string
+	VersionID string
+}
+
+func (e VersionNotFound) Error() string {
+	return "Object version not found: " + e.Object + "#" + e.VersionID
+}
+
 // ObjectCorrupted object found to be corrupted
 type ObjectCorrupted struct {
 	Object string
@@ -33,6 +33,7 @@ type ObjectStorage interface {
 	SetBucketMetadata(bucket string, metadata map[string]string) error
 	ListBuckets() (map[string]BucketMetadata, error)
 	MakeBucket(bucket, acl string) error
+	DeleteBucket(bucket string) error
 
 	// Bucket operations
 	ListObjects(bucket, prefix, marker, delim string, maxKeys int) (objects []string, prefixes []string, isTruncated bool, err error)
@@ -40,7 +41,18 @@ type ObjectStorage interface {
 	// Object operations
 	GetObject(bucket, object string) (io.ReadCloser, int64, error)
 	GetObjectMetadata(bucket, object string) (ObjectMetadata, error)
-	PutObject(bucket, object, expectedMD5Sum string, reader io.ReadCloser, metadata map[string]string) (string, error)
+	PutObject(bucket, object, expectedMD5Sum string, reader io.ReadCloser, metadata, tags map[string]string, grants []Grant) (ObjectMetadata, error)
+	DeleteObject(bucket, object string) error
+	DeleteObjects(bucket string, objects []string) (map[string]error, error)
+	SetObjectTags(bucket, object string, tags map[string]string) error
+	SetObjectACL(bucket, object string, grants []Grant) error
+	ReplaceObjectMetadata(bucket, object string, metadata map[string]string, grants []Grant) (ObjectMetadata, error)
+
+	// Object version operations
+	GetObjectVersion(bucket, object, versionID string) (io.ReadCloser, int64, error)
+	GetObjectVersionMetadata(bucket, object, versionID string) (ObjectMetadata, error)
+	DeleteObjectVersion(bucket, object, versionID string) error
+	ListObjectVersions(bucket, prefix string) ([]ObjectMetadata, error)
 }
 
 // Management is a donut management system interface
@@ -0,0 +1,353 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package donut
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/iodine"
+)
+
+// versioning status of a bucket
+const (
+	versioningEnabled   = "Enabled"
+	versioningSuspended = "Suspended"
+)
+
+// nullVersionID - version id of objects written while versioning was not enabled
+const nullVersionID = "null"
+
+// newVersionID - generate a new version id
+func newVersionID(bucket, object string) string {
+	id := []byte(strconv.FormatInt(rand.Int63(), 10) + bucket + object + time.Now().String())
+	versionIDSum := sha512.Sum512(id)
+	return base64.URLEncoding.EncodeToString(versionIDSum[:])[:32]
+}
+
+// objectVersionName - name the data of a version of an object is kept under, the null version
+// is kept under the object name itself
+func objectVersionName(object, versionID string) string {
+	if versionID == "" || versionID == nullVersionID {
+		return object
+	}
+	return object + "$" + versionID
+}
+
+// isVersionMatch - version ids are equal, the null version has an empty id
+func isVersionMatch(versionID, otherVersionID string) bool {
+	if versionID == "" {
+		versionID = nullVersionID
+	}
+	if otherVersionID == "" {
+		otherVersionID = nullVersionID
+	}
+	return versionID == otherVersionID
+}
+
+// getVersionID - version id of a current object, bucket objects written without versioning are kept as 1
+func getVersionID(value interface{}) string {
+	if versionID, ok := value.(string); ok {
+		return versionID
+	}
+	return ""
+}
+
+// setCurrentVersion - record a version as the current object
+func setCurrentVersion(bucketMetadata *BucketMetadata, object, versionID string) {
+	if versionID == "" {
+		bucketMetadata.BucketObjects[object] = 1
+		return
+	}
+	bucketMetadata.BucketObjects[object] = versionID
+}
+
+// setObjectVersions - replace the earlier versions of an object
+func setObjectVersions(bucketMetadata *BucketMetadata, object string, versions []ObjectVersion) {
+	if len(versions) == 0 {
+		delete(bucketMetadata.ObjectVersions, object)
+		return
+	}
+	if bucketMetadata.ObjectVersions == nil {
+		bucketMetadata.ObjectVersions = make(map[string][]ObjectVersion)
+	}
+	bucketMetadata.ObjectVersions[object] = versions
+}
+
+// archiveObject - move the current object to the earlier versions of its key, with versioning suspended
+// there is only one null version which is replaced instead
+func (dt donut) archiveObject(bucketMetadata *BucketMetadata, bucket, object string) error {
+	versions := bucketMetadata.ObjectVersions[object]
+	if bucketMetadata.Versioning == versioningSuspended {
+		for i, version := range versions {
+			if version.VersionID != "" {
+				continue
+			}
+			if !version.DeleteMarker {
+				if err := dt.buckets[bucket].DeleteObject(object, ""); err != nil {
+					return iodine.New(err, nil)
+				}
+			}
+			versions = append(versions[:i:i], versions[i+1:]...)
+			break
+		}
+	}
+	if value, ok := bucketMetadata.BucketObjects[object]; ok {
+		versionID := getVersionID(value)
+		delete(bucketMetadata.BucketObjects, object)
+		switch {
+		case versionID == "" && bucketMetadata.Versioning == versioningSuspended:
+			if err := dt.buckets[bucket].DeleteObject(object, ""); err != nil {
+				return iodine.New(err, nil)
+			}
+		default:
+			objectMetadata, err := dt.buckets[bucket].GetObjectMetadata(object, versionID)
+			if err != nil {
+				return iodine.New(err, nil)
+			}
+			version := ObjectVersion{
+				VersionID: versionID,
+				Created:   objectMetadata.Created,
+			}
+			versions = append([]ObjectVersion{version}, versions...)
+		}
+	}
+	setObjectVersions(bucketMetadata, object, versions)
+	return nil
+}
+
+// createDeleteMarker - replace the current object by a delete marker, its earlier versions are kept
+func (dt donut) createDeleteMarker(bucketMetadata *BucketMetadata, bucket, object string) error {
+	if err := dt.archiveObject(bucketMetadata, bucket, object); err != nil {
+		return iodine.New(err, nil)
+	}
+	marker := ObjectVersion{
+		DeleteMarker: true,
+		Created:      time.Now().UTC(),
+	}
+	if bucketMetadata.Versioning == versioningEnabled {
+		marker.VersionID = newVersionID(bucket, object)
+	}
+	setObjectVersions(bucketMetadata, object, append([]ObjectVersion{marker}, bucketMetadata.ObjectVersions[object]...))
+	return nil
+}
+
+// getObjectVersions - every version of an object newest first, the current object has no creation time
+func getObjectVersions(bucketMetadata BucketMetadata, object string) []ObjectVersion {
+	var versions []ObjectVersion
+	if value, ok := bucketMetadata.BucketObjects[object]; ok {
+		versions = append(versions, ObjectVersion{VersionID: getVersionID(value)})
+	}
+	return append(versions, bucketMetadata.ObjectVersions[object]...)
+}
+
+// findObjectVersion - position of a version among every version of an object, an empty version id finds the latest one
+func findObjectVersion(versions []ObjectVersion, versionID string) (int, bool) {
+	for i, version := range versions {
+		if versionID == "" || isVersionMatch(version.VersionID, versionID) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// getVersionMetadata - object metadata of a version, delete markers have nothing but their version and creation time
+func (dt donut) getVersionMetadata(bucket, object string, version ObjectVersion) (ObjectMetadata, error) {
+	if version.DeleteMarker {
+		return ObjectMetadata{
+			Created:      version.Created,
+			Bucket:       bucket,
+			Object:       object,
+			VersionID:    version.VersionID,
+			DeleteMarker: true,
+		}, nil
+	}
+	objectMetadata, err := dt.buckets[bucket].GetObjectMetadata(object, version.VersionID)
+	if err != nil {
+		return ObjectMetadata{}, iodine.New(err, nil)
+	}
+	return objectMetadata, nil
+}
+
+// getObjectVersion - find a version of an object, caller holds the lock
+func (dt donut) getObjectVersion(bucket, object, versionID string) (ObjectMetadata, error) {
+	errParams := map[string]string{
+		"bucket":    bucket,
+		"object":    object,
+		"versionID": versionID,
+	}
+	if err := dt.listDonutBuckets(); err != nil {
+		return ObjectMetadata{}, iodine.New(err, errParams)
+	}
+	if _, ok := dt.buckets[bucket]; !ok {
+		return ObjectMetadata{}, iodine.New(BucketNotFound{Bucket: bucket}, errParams)
+	}
+	bucketMeta, err := dt.getDonutBucketMetadata()
+	if err != nil {
+		return ObjectMetadata{}, iodine.New(err, errParams)
+	}
+	versions := getObjectVersions(bucketMeta.Buckets[bucket], object)
+	i, ok := findObjectVersion(versions, versionID)
+	if !ok {
+		if versionID == "" {
+			return ObjectMetadata{}, iodine.New(ObjectNotFound{Object: object}, errParams)
+		}
+		return ObjectMetadata{}, iodine.New(VersionNotFound{Object: object, VersionID: versionID}, errParams)
+	}
+	objectMetadata, err := dt.getVersionMetadata(bucket, object, versions[i])
+	if err != nil {
+		return ObjectMetadata{}, iodine.New(err, errParams)
+	}
+	objectMetadata.IsLatest = i == 0
+	return objectMetadata, nil
+}
+
+// GetObjectVersion - get a version of an object, an empty version id gets the latest one
+func (dt donut) GetObjectVersion(bucket, object, versionID string) (io.ReadCloser, int64, error) {
+	dt.lock.RLock()
+	defer dt.lock.RUnlock()
+	objectMetadata, err := dt.getObjectVersion(bucket, object, versionID)
+	if err != nil {
+		return nil, 0, iodine.New(err, nil)
+	}
+	// delete markers have no data to read
+	if objectMetadata.DeleteMarker {
+		return nil, 0, iodine.New(ObjectNotFound{Object: object}, nil)
+	}
+	return dt.buckets[bucket].ReadObject(object, objectMetadata.VersionID)
+}
+
+// GetObjectVersionMetadata - get the metadata of a version of an object, an empty version id gets the latest one
+func (dt donut) GetObjectVersionMetadata(bucket, object, versionID string) (ObjectMetadata, error) {
+	dt.lock.RLock()
+	defer dt.lock.RUnlock()
+	objectMetadata, err := dt.getObjectVersion(bucket, object, versionID)
+	if err != nil {
+		return ObjectMetadata{}, iodine.New(err, nil)
+	}
+	return objectMetadata, nil
+}
+
+// DeleteObjectVersion - remove a version of an object for good, the latest remaining version
+// becomes the current object unless it is a delete marker
+func (dt donut) DeleteObjectVersion(bucket, object, versionID string) error {
+	dt.lock.Lock()
+	defer dt.lock.Unlock()
+	errParams := map[string]string{
+		"bucket":    bucket,
+		"object":    object,
+		"versionID": versionID,
+	}
+	if bucket == "" || strings.TrimSpace(bucket) == "" {
+		return iodine.New(InvalidArgument{}, errParams)
+	}
+	if object == "" || strings.TrimSpace(object) == "" {
+		return iodine.New(InvalidArgument{}, errParams)
+	}
+	if err := dt.listDonutBuckets(); err != nil {
+		return iodine.New(err, errParams)
+	}
+	if _, ok := dt.buckets[bucket]; !ok {
+		return iodine.New(BucketNotFound{Bucket: bucket}, errParams)
+	}
+	bucketMeta, err := dt.getDonutBucketMetadata()
+	if err != nil {
+		return iodine.New(err, errParams)
+	}
+	bucketMetadata := bucketMeta.Buckets[bucket]
+	versions := getObjectVersions(bucketMetadata, object)
+	i, ok := findObjectVersion(versions, versionID)
+	if !ok {
+		return iodine.New(VersionNotFound{Object: object, VersionID: versionID}, errParams)
+	}
+	if !versions[i].DeleteMarker {
+		if err := dt.buckets[bucket].DeleteObject(object, versions[i].VersionID); err != nil {
+			return iodine.New(err, errParams)
+		}
+	}
+	earlierVersions := bucketMetadata.ObjectVersions[object]
+	_, hasCurrent := bucketMetadata.BucketObjects[object]
+	switch {
+	case hasCurrent && i == 0:
+		delete(bucketMetadata.BucketObjects, object)
+	case hasCurrent:
+		earlierVersions = append(earlierVersions[:i-1:i-1], earlierVersions[i:]...)
+	default:
+		earlierVersions = append(earlierVersions[:i:i], earlierVersions[i+1:]...)
+	}
+	if _, ok := bucketMetadata.BucketObjects[object]; !ok && len(earlierVersions) > 0 && !earlierVersions[0].DeleteMarker {
+		setCurrentVersion(&bucketMetadata, object, earlierVersions[0].VersionID)
+		earlierVersions = earlierVersions[1:]
+	}
+	setObjectVersions(&bucketMetadata, object, earlierVersions)
+	bucketMeta.Buckets[bucket] = bucketMetadata
+	if err := dt.setDonutBucketMetadata(bucketMeta); err != nil {
+		return iodine.New(err, errParams)
+	}
+	return nil
+}
+
+// ListObjectVersions - every version of the objects under a prefix, sorted by object name and newest first
+func (dt donut) ListObjectVersions(bucket, prefix string) ([]ObjectMetadata, error) {
+	dt.lock.RLock()
+	defer dt.lock.RUnlock()
+	errParams := map[string]string{
+		"bucket": bucket,
+		"prefix": prefix,
+	}
+	if err := dt.listDonutBuckets(); err != nil {
+		return nil, iodine.New(err, errParams)
+	}
+	if _, ok := dt.buckets[bucket]; !ok {
+		return nil, iodine.New(BucketNotFound{Bucket: bucket}, errParams)
+	}
+	bucketMeta, err := dt.getDonutBucketMetadata()
+	if err != nil {
+		return nil, iodine.New(err, errParams)
+	}
+	bucketMetadata := bucketMeta.Buckets[bucket]
+	var objects []string
+	for object := range bucketMetadata.BucketObjects {
+		if strings.HasPrefix(object, prefix) {
+			objects = append(objects, object)
+		}
+	}
+	for object := range bucketMetadata.ObjectVersions {
+		if _, ok := bucketMetadata.BucketObjects[object]; !ok && strings.HasPrefix(object, prefix) {
+			objects = append(objects, object)
+		}
+	}
+	sort.Strings(objects)
+	var results []ObjectMetadata
+	for _, object := range objects {
+		for i, version := range getObjectVersions(bucketMetadata, object) {
+			objectMetadata, err := dt.getVersionMetadata(bucket, object, version)
+			if err != nil {
+				return nil, iodine.New(err, errParams)
+			}
+			objectMetadata.IsLatest = i == 0
+			results = append(results, objectMetadata)
+		}
+	}
+	return results, nil
+}
@@ -47,8 +47,18 @@ func APITestSuite(c *check.C, create func() Driver) {
 	testNonExistantObjectInBucket(c, create)
 	testGetDirectoryReturnsObjectNotFound(c, create)
 	testDefaultContentType(c, create)
+	testDeleteObject(c, create)
+	testDeleteObjects(c, create)
+	testCopyObject(c, create)
+	testFailedWriteKeepsVersion(c, create)
+	testObjectMetadata(c, create)
+	testObjectTags(c, create)
+	testObjectACL(c, create)
+	testDeleteBucket(c, create)
+	testBucketResources(c, create)
 	testMultipartObjectCreation(c, create)
 	testMultipartObjectAbort(c, create)
+	testMultipartObjectVersioning(c, create)
 }
 
 func testCreateBucket(c *check.C, create func() Driver) {
@@ -65,7 +75,11 @@ func testMultipartObjectCreation(c *check.C, create func() Driver) {
 	}
 	err := drivers.CreateBucket("bucket", "")
 	c.Assert(err, check.IsNil)
-	uploadID, err := drivers.NewMultipartUpload("bucket", "key", "")
+	metadata := map[string]string{"X-Amz-Meta-Owner": "minio"}
+	tags := map[string]string{"project": "minio"}
+	grants := []Grant{{GranteeURI: "http://acs.amazonaws.com/groups/global/AllUsers", Permission: "READ"}}
+	attributes := ObjectAttributes{Metadata: metadata, Tags: tags, ACL: grants}
+	uploadID, err := drivers.NewMultipartUpload("bucket", "key", "text/plain", attributes)
 	c.Assert(err, check.IsNil)
 
 	parts := make(map[int]string)
@@ -90,9 +104,17 @@ func testMultipartObjectCreation(c *check.C, create func() Driver) {
 		parts[i] = calculatedmd5sum
 	}
 	finalExpectedmd5SumHex := hex.EncodeToString(finalHasher.Sum(nil))
-	calculatedFinalmd5Sum, err := drivers.CompleteMultipartUpload("bucket", "key", uploadID, parts)
+	completedMetadata, err := drivers.CompleteMultipartUpload("bucket", "key", uploadID, parts)
 	c.Assert(err, check.IsNil)
-	c.Assert(calculatedFinalmd5Sum, check.Equals, finalExpectedmd5SumHex)
+	c.Assert(completedMetadata.Md5, check.Equals, finalExpectedmd5SumHex)
+
+	// the object gets the content type, metadata, tags and grants the upload was started with
+	objectMetadata, err := drivers.GetObjectMetadata("bucket", "key")
+	c.Assert(err, check.IsNil)
+	c.Assert(objectMetadata.ContentType, check.Equals, "text/plain")
+	c.Assert(objectMetadata.Metadata, check.DeepEquals, metadata)
+	c.Assert(objectMetadata.Tags, check.DeepEquals, tags)
+	c.Assert(objectMetadata.ACL, check.DeepEquals, grants)
 }
 
 func testMultipartObjectAbort(c *check.C, create func() Driver) {
@@ -103,7 +125,7 @@ func testMultipartObjectAbort(c *check.C, create func() Driver) {
 	}
 	err := drivers.CreateBucket("bucket", "")
 	c.Assert(err, check.IsNil)
-	uploadID, err := drivers.NewMultipartUpload("bucket", "key", "")
+	uploadID, err := drivers.NewMultipartUpload("bucket", "key", "", ObjectAttributes{})
 	c.Assert(err, check.IsNil)
 
 	parts := make(map[int]string)
@@ -129,6 +151,64 @@ func testMultipartObjectAbort(c *check.C, create func() Driver) {
 	c.Assert(err, check.IsNil)
 }
 
+func testMultipartObjectVersioning(c *check.C, create func() Driver) {
+	drivers := create()
+	switch {
+	case reflect.TypeOf(drivers).String() == "*donut.donutDriver":
+		return
+	case reflect.TypeOf(drivers).String() == "*memory.memoryDriver":
+		return
+	}
+	err := drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+	err = drivers.SetBucketVersioning("bucket", VersioningEnabled)
+	c.Assert(err, check.IsNil)
+	_, err = drivers.CreateObject("bucket", "key", "", "", int64(len("one")), bytes.NewBufferString("one"), ObjectAttributes{})
+	c.Assert(err, check.IsNil)
+
+	uploadObject := func(data string) ObjectMetadata {
+		uploadID, err := drivers.NewMultipartUpload("bucket", "key", "", ObjectAttributes{})
+		c.Assert(err, check.IsNil)
+		hasher := md5.New()
+		hasher.Write([]byte(data))
+		md5sum, err := drivers.CreateObjectPart("bucket", "key", uploadID, 1, "", base64.StdEncoding.EncodeToString(hasher.Sum(nil)),
+			int64(len(data)), bytes.NewBufferString(data))
+		c.Assert(err, check.IsNil)
+		metadata, err := drivers.CompleteMultipartUpload("bucket", "key", uploadID, map[int]string{1: md5sum})
+		c.Assert(err, check.IsNil)
+		return metadata
+	}
+
+	// an upload over an existing object, and over a delete marker, adds a new version
+	two := uploadObject("two")
+	c.Assert(two.VersionID, check.Not(check.Equals), "")
+	err = drivers.DeleteObject("bucket", "key")
+	c.Assert(err, check.IsNil)
+	three := uploadObject("three")
+	c.Assert(three.VersionID, check.Not(check.Equals), "")
+	c.Assert(three.VersionID, check.Not(check.Equals), two.VersionID)
+
+	var byteBuffer bytes.Buffer
+	_, err = drivers.GetObject(&byteBuffer, "bucket", "key")
+	c.Assert(err, check.IsNil)
+	c.Assert(byteBuffer.String(), check.Equals, "three")
+	byteBuffer.Reset()
+	_, err = drivers.GetObjectVersion(&byteBuffer, "bucket", "key", two.VersionID)
+	c.Assert(err, check.IsNil)
+	c.Assert(byteBuffer.String(), check.Equals, "two")
+
+	versions, _, err := drivers.ListObjectVersions("bucket", BucketResourcesMetadata{Maxkeys: 1000})
+	c.Assert(err, check.IsNil)
+	c.Assert(len(versions), check.Equals, 4)
+	versionIDs := make(map[string]bool)
+	for _, version := range versions {
+		versionIDs[version.VersionID] = true
+	}
+	c.Assert(len(versionIDs), check.Equals, 4)
+	c.Assert(versions[0].VersionID, check.Equals, three.VersionID)
+	c.Assert(versions[0].IsLatest, check.Equals, true)
+}
+
 func testMultipleObjectCreation(c *check.C, create func() Driver) {
 	objects := make(map[string][]byte)
 	drivers := create()
@@ -149,9 +229,9 @@ func testMultipleObjectCreation(c *check.C, create func() Driver) {
 		key := "obj" + strconv.Itoa(i)
 		objects[key] = []byte(randomString)
 		calculatedmd5sum, err := drivers.CreateObject("bucket", key, "", expectedmd5Sum, int64(len(randomString)),
-			bytes.NewBufferString(randomString))
+			bytes.NewBufferString(randomString), ObjectAttributes{})
 		c.Assert(err, check.IsNil)
-		c.Assert(calculatedmd5sum, check.Equals, expectedmd5Sumhex)
+		c.Assert(calculatedmd5sum.Md5, check.Equals, expectedmd5Sumhex)
 	}
 
 	// ensure no duplicate etags
@@ -183,7 +263,7 @@ func testPaging(c *check.C, create func() Driver) {
 	// check before paging occurs
 	for i := 0; i < 5; i++ {
 		key := "obj" + strconv.Itoa(i)
-		drivers.CreateObject("bucket", key, "", "", int64(len(key)), bytes.NewBufferString(key))
+		drivers.CreateObject("bucket", key, "", "", int64(len(key)), bytes.NewBufferString(key), ObjectAttributes{})
 		resources.Maxkeys = 5
 		resources.Prefix = ""
 		objects, resources, err = drivers.ListObjects("bucket", resources)
@@ -194,7 +274,7 @@ func testPaging(c *check.C, create func() Driver) {
 	// check after paging occurs pages work
 	for i := 6; i <= 10; i++ {
 		key := "obj" + strconv.Itoa(i)
-		drivers.CreateObject("bucket", key, "", "", int64(len(key)), bytes.NewBufferString(key))
+		drivers.CreateObject("bucket", key, "", "", int64(len(key)), bytes.NewBufferString(key), ObjectAttributes{})
 		resources.Maxkeys = 5
 		resources.Prefix = ""
 		objects, resources, err = drivers.ListObjects("bucket", resources)
@@ -204,8 +284,8 @@ func testPaging(c *check.C, create func() Driver) {
 	}
 	// check paging with prefix at end returns less objects
 	{
-		drivers.CreateObject("bucket", "newPrefix", "", "", int64(len("prefix1")), bytes.NewBufferString("prefix1"))
-		drivers.CreateObject("bucket", "newPrefix2", "", "", int64(len("prefix2")), bytes.NewBufferString("prefix2"))
+		drivers.CreateObject("bucket", "newPrefix", "", "", int64(len("prefix1")), bytes.NewBufferString("prefix1"), ObjectAttributes{})
+		drivers.CreateObject("bucket", "newPrefix2", "", "", int64(len("prefix2")), bytes.NewBufferString("prefix2"), ObjectAttributes{})
 		resources.Prefix = "new"
 		resources.Maxkeys = 5
 		objects, resources, err = drivers.ListObjects("bucket", resources)
@@ -228,8 +308,8 @@ func testPaging(c *check.C, create func() Driver) {
 
 	// check delimited results with delimiter and prefix
 	{
-		drivers.CreateObject("bucket", "this/is/delimited", "", "", int64(len("prefix1")), bytes.NewBufferString("prefix1"))
-		drivers.CreateObject("bucket", "this/is/also/a/delimited/file", "", "", int64(len("prefix2")), bytes.NewBufferString("prefix2"))
+		drivers.CreateObject("bucket", "this/is/delimited", "", "", int64(len("prefix1")), bytes.NewBufferString("prefix1"), ObjectAttributes{})
+		drivers.CreateObject("bucket", "this/is/also/a/delimited/file", "", "", int64(len("prefix2")), bytes.NewBufferString("prefix2"), ObjectAttributes{})
 		var prefixes []string
 		resources.CommonPrefixes = prefixes // allocate new everytime
 		resources.Delimiter = "/"
@@ -307,14 +387,14 @@ func testObjectOverwriteFails(c *check.C, create func() Driver) {
 	hasher1.Write([]byte("one"))
 	md5Sum1 := base64.StdEncoding.EncodeToString(hasher1.Sum(nil))
 	md5Sum1hex := hex.EncodeToString(hasher1.Sum(nil))
-	md5Sum11, err := drivers.CreateObject("bucket", "object", "", md5Sum1, int64(len("one")), bytes.NewBufferString("one"))
+	md5Sum11, err := drivers.CreateObject("bucket", "object", "", md5Sum1, int64(len("one")), bytes.NewBufferString("one"), ObjectAttributes{})
 	c.Assert(err, check.IsNil)
-	c.Assert(md5Sum1hex, check.Equals, md5Sum11)
+	c.Assert(md5Sum1hex, check.Equals, md5Sum11.Md5)
 
 	hasher2 := md5.New()
 	hasher2.Write([]byte("three"))
 	md5Sum2 := base64.StdEncoding.EncodeToString(hasher2.Sum(nil))
-	_, err = drivers.CreateObject("bucket", "object", "", md5Sum2, int64(len("three")), bytes.NewBufferString("three"))
+	_, err = drivers.CreateObject("bucket", "object", "", md5Sum2, int64(len("three")), bytes.NewBufferString("three"), ObjectAttributes{})
 	c.Assert(err, check.Not(check.IsNil))
 
 	var bytesBuffer bytes.Buffer
@@ -326,7 +406,7 @@ func testObjectOverwriteFails(c *check.C, create func() Driver) {
 
 func testNonExistantBucketOperations(c *check.C, create func() Driver) {
 	drivers := create()
-	_, err := drivers.CreateObject("bucket", "object", "", "", int64(len("one")), bytes.NewBufferString("one"))
+	_, err := drivers.CreateObject("bucket", "object", "", "", int64(len("one")), bytes.NewBufferString("one"), ObjectAttributes{})
 	c.Assert(err, check.Not(check.IsNil))
 }
 
@@ -358,9 +438,9 @@ func testPutObjectInSubdir(c *check.C, create func() Driver) {
 	md5Sum1 := base64.StdEncoding.EncodeToString(hasher.Sum(nil))
 	md5Sum1hex := hex.EncodeToString(hasher.Sum(nil))
 	md5Sum11, err := drivers.CreateObject("bucket", "dir1/dir2/object", "", md5Sum1, int64(len("hello world")),
-		bytes.NewBufferString("hello world"))
+		bytes.NewBufferString("hello world"), ObjectAttributes{})
 	c.Assert(err, check.IsNil)
-	c.Assert(md5Sum11, check.Equals, md5Sum1hex)
+	c.Assert(md5Sum11.Md5, check.Equals, md5Sum1hex)
 
 	var bytesBuffer bytes.Buffer
 	length, err := drivers.GetObject(&bytesBuffer, "bucket", "dir1/dir2/object")
@@ -455,7 +535,7 @@ func testGetDirectoryReturnsObjectNotFound(c *check.C, create func() Driver) {
 	c.Assert(err, check.IsNil)
 
 	_, err = drivers.CreateObject("bucket", "dir1/dir2/object", "", "", int64(len("hello world")),
-		bytes.NewBufferString("hello world"))
+		bytes.NewBufferString("hello world"), ObjectAttributes{})
 	c.Assert(err, check.IsNil)
 
 	var byteBuffer bytes.Buffer
@@ -499,24 +579,552 @@ func testDefaultContentType(c *check.C, create func() Driver) {
 	c.Assert(err, check.IsNil)
 
 	// test empty
-	_, err = drivers.CreateObject("bucket", "one", "", "", int64(len("one")), bytes.NewBufferString("one"))
+	_, err = drivers.CreateObject("bucket", "one", "", "", int64(len("one")), bytes.NewBufferString("one"), ObjectAttributes{})
 	metadata, err := drivers.GetObjectMetadata("bucket", "one")
 	c.Assert(err, check.IsNil)
 	c.Assert(metadata.ContentType, check.Equals, "application/octet-stream")
 
 	// test custom
-	drivers.CreateObject("bucket", "two", "application/text", "", int64(len("two")), bytes.NewBufferString("two"))
+	drivers.CreateObject("bucket", "two", "application/text", "", int64(len("two")), bytes.NewBufferString("two"), ObjectAttributes{})
 	metadata, err = drivers.GetObjectMetadata("bucket", "two")
 	c.Assert(err, check.IsNil)
 	c.Assert(metadata.ContentType, check.Equals, "application/text")
 
 	// test trim space
-	drivers.CreateObject("bucket", "three", "\tapplication/json    ", "", int64(len("three")), bytes.NewBufferString("three"))
+	drivers.CreateObject("bucket", "three", "\tapplication/json    ", "", int64(len("three")), bytes.NewBufferString("three"), ObjectAttributes{})
 	metadata, err = drivers.GetObjectMetadata("bucket", "three")
 	c.Assert(err, check.IsNil)
 	c.Assert(metadata.ContentType, check.Equals, "application/json")
 }
 
+func testDeleteObject(c *check.C, create func() Driver) {
+	drivers := create()
+	err := drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+
+	_, err = drivers.CreateObject("bucket", "dir1/dir2/object", "", "", int64(len("hello world")),
+		bytes.NewBufferString("hello world"), ObjectAttributes{})
+	c.Assert(err, check.IsNil)
+	_, err = drivers.CreateObject("bucket", "object", "", "", int64(len("hello world")),
+		bytes.NewBufferString("hello world"), ObjectAttributes{})
+	c.Assert(err, check.IsNil)
+
+	err = drivers.DeleteObject("bucket", "dir1/dir2/object")
+	c.Assert(err, check.IsNil)
+
+	_, err = drivers.GetObjectMetadata("bucket", "dir1/dir2/object")
+	switch err := iodine.ToError(err).(type) {
+	case ObjectNotFound:
+		{
+			c.Assert(err.Object, check.Equals, "dir1/dir2/object")
+		}
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "ObjectNotFound")
+		}
+	}
+
+	// deleting again reports object not found
+	err = drivers.DeleteObject("bucket", "dir1/dir2/object")
+	switch iodine.ToError(err).(type) {
+	case ObjectNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "ObjectNotFound")
+		}
+	}
+
+	err = drivers.DeleteObject("nonexistantbucket", "object")
+	c.Assert(err, check.Not(check.IsNil))
+
+	// remaining objects are untouched and deleted keys can be recreated
+	objects, _, err := drivers.ListObjects("bucket", BucketResourcesMetadata{Maxkeys: 1000})
+	c.Assert(err, check.IsNil)
+	c.Assert(len(objects), check.Equals, 1)
+	c.Assert(objects[0].Key, check.Equals, "object")
+
+	_, err = drivers.CreateObject("bucket", "dir1/dir2/object", "", "", int64(len("hello again")),
+		bytes.NewBufferString("hello again"), ObjectAttributes{})
+	c.Assert(err, check.IsNil)
+
+	var byteBuffer bytes.Buffer
+	_, err = drivers.GetObject(&byteBuffer, "bucket", "dir1/dir2/object")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(byteBuffer.Bytes()), check.Equals, "hello again")
+}
+
+func testDeleteObjects(c *check.C, create func() Driver) {
+	drivers := create()
+	err := drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+
+	for _, key := range []string{"object1", "dir/object2", "object3"} {
+		_, err = drivers.CreateObject("bucket", key, "", "", int64(len("hello world")),
+			bytes.NewBufferString("hello world"), ObjectAttributes{})
+		c.Assert(err, check.IsNil)
+	}
+
+	errs, err := drivers.DeleteObjects("bucket", []string{"object1", "dir/object2", "nonexistant"})
+	c.Assert(err, check.IsNil)
+	c.Assert(len(errs), check.Equals, 1)
+	switch iodine.ToError(errs["nonexistant"]).(type) {
+	case ObjectNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(errs["nonexistant"], check.Equals, "ObjectNotFound")
+		}
+	}
+
+	objects, _, err := drivers.ListObjects("bucket", BucketResourcesMetadata{Maxkeys: 1000})
+	c.Assert(err, check.IsNil)
+	c.Assert(len(objects), check.Equals, 1)
+	c.Assert(objects[0].Key, check.Equals, "object3")
+
+	_, err = drivers.DeleteObjects("nonexistantbucket", []string{"object3"})
+	switch iodine.ToError(err).(type) {
+	case BucketNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "BucketNotFound")
+		}
+	}
+}
+
+func testCopyObject(c *check.C, create func() Driver) {
+	drivers := create()
+	err := drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+	err = drivers.CreateBucket("bucket2", "")
+	c.Assert(err, check.IsNil)
+
+	md5, err := drivers.CreateObject("bucket", "object", "text/plain", "", int64(len("hello world")),
+		bytes.NewBufferString("hello world"), ObjectAttributes{})
+	c.Assert(err, check.IsNil)
+
+	// copy keeps the content type of the source
+	metadata, err := drivers.CopyObject("bucket", "object", "bucket2", "dir/copy", "", nil, nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(metadata.Key, check.Equals, "dir/copy")
+	c.Assert(metadata.Md5, check.Equals, md5.Md5)
+	c.Assert(metadata.Size, check.Equals, int64(len("hello world")))
+	c.Assert(metadata.ContentType, check.Equals, "text/plain")
+
+	var byteBuffer bytes.Buffer
+	_, err = drivers.GetObject(&byteBuffer, "bucket2", "dir/copy")
+	c.Assert(err, check.IsNil)
+	c.Assert(byteBuffer.String(), check.Equals, "hello world")
+
+	// copy with a replaced content type
+	metadata, err = drivers.CopyObject("bucket", "object", "bucket", "copy", "application/json", nil, nil)
+	c.Assert(err, check.IsNil)
+	metadata, err = drivers.GetObjectMetadata("bucket", "copy")
+	c.Assert(err, check.IsNil)
+	c.Assert(metadata.ContentType, check.Equals, "application/json")
+	c.Assert(metadata.Md5, check.Equals, md5.Md5)
+
+	// the source is untouched
+	metadata, err = drivers.GetObjectMetadata("bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(metadata.ContentType, check.Equals, "text/plain")
+
+	// objects are never overwritten by a copy
+	_, err = drivers.CopyObject("bucket", "object", "bucket", "copy", "", nil, nil)
+	switch iodine.ToError(err).(type) {
+	case ObjectExists:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "ObjectExists")
+		}
+	}
+
+	_, err = drivers.CopyObject("bucket", "nonexistant", "bucket", "copy2", "", nil, nil)
+	switch iodine.ToError(err).(type) {
+	case ObjectNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "ObjectNotFound")
+		}
+	}
+
+	_, err = drivers.CopyObject("bucket", "object", "nonexistantbucket", "copy", "", nil, nil)
+	c.Assert(err, check.Not(check.IsNil))
+
+	// except for an object copied onto itself, which replaces its metadata
+	_, err = drivers.CopyObject("bucket", "object", "bucket", "object", "application/json", map[string]string{"X-Amz-Meta-Owner": "minio"}, nil)
+	c.Assert(err, check.IsNil)
+	metadata, err = drivers.GetObjectMetadata("bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(metadata.ContentType, check.Equals, "application/json")
+	c.Assert(metadata.Metadata, check.DeepEquals, map[string]string{"X-Amz-Meta-Owner": "minio"})
+	c.Assert(metadata.Md5, check.Equals, md5.Md5)
+
+	byteBuffer.Reset()
+	_, err = drivers.GetObject(&byteBuffer, "bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(byteBuffer.String(), check.Equals, "hello world")
+}
+
+func testFailedWriteKeepsVersion(c *check.C, create func() Driver) {
+	for _, status := range []string{VersioningEnabled, VersioningSuspended} {
+		drivers := create()
+		switch {
+		case reflect.TypeOf(drivers).String() == "*memory.memoryDriver":
+			return
+		}
+		err := drivers.CreateBucket("bucket", "")
+		c.Assert(err, check.IsNil)
+		err = drivers.SetBucketVersioning("bucket", status)
+		c.Assert(err, check.IsNil)
+
+		_, err = drivers.CreateObject("bucket", "object", "", "", int64(len("one")), bytes.NewBufferString("one"), ObjectAttributes{})
+		c.Assert(err, check.IsNil)
+
+		// neither an invalid md5, a mismatching md5 nor a truncated upload replace the current version
+		_, err = drivers.CreateObject("bucket", "object", "", "invalid", int64(len("two")), bytes.NewBufferString("two"), ObjectAttributes{})
+		c.Assert(err, check.Not(check.IsNil))
+		_, err = drivers.CreateObject("bucket", "object", "", "NWJiZjVhNTIzMjhlNzQzOWFlNmU3MTlkZmU3MTIyMDA=", int64(len("two")),
+			bytes.NewBufferString("two"), ObjectAttributes{})
+		c.Assert(err, check.Not(check.IsNil))
+		_, err = drivers.CreateObject("bucket", "object", "", "", int64(len("three")), bytes.NewBufferString("thr"), ObjectAttributes{})
+		c.Assert(err, check.Not(check.IsNil))
+
+		var byteBuffer bytes.Buffer
+		_, err = drivers.GetObject(&byteBuffer, "bucket", "object")
+		c.Assert(err, check.IsNil)
+		c.Assert(byteBuffer.String(), check.Equals, "one")
+
+		versions, _, err := drivers.ListObjectVersions("bucket", BucketResourcesMetadata{Maxkeys: 1000})
+		c.Assert(err, check.IsNil)
+		c.Assert(len(versions), check.Equals, 1)
+		c.Assert(versions[0].IsLatest, check.Equals, true)
+
+		// a successful write still takes the place of the current version, with versioning suspended
+		// it replaces the null version
+		_, err = drivers.CreateObject("bucket", "object", "", "", int64(len("four")), bytes.NewBufferString("four"), ObjectAttributes{})
+		c.Assert(err, check.IsNil)
+		byteBuffer.Reset()
+		_, err = drivers.GetObject(&byteBuffer, "bucket", "object")
+		c.Assert(err, check.IsNil)
+		c.Assert(byteBuffer.String(), check.Equals, "four")
+		versions, _, err = drivers.ListObjectVersions("bucket", BucketResourcesMetadata{Maxkeys: 1000})
+		c.Assert(err, check.IsNil)
+		if status == VersioningSuspended {
+			c.Assert(len(versions), check.Equals, 1)
+			c.Assert(versions[0].VersionID, check.Equals, "")
+		} else {
+			c.Assert(len(versions), check.Equals, 2)
+		}
+	}
+}
+
+func testObjectMetadata(c *check.C, create func() Driver) {
+	drivers := create()
+	err := drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+
+	metadata := map[string]string{
+		"X-Amz-Meta-Owner":    "minio",
+		"Content-Encoding":    "gzip",
+		"Content-Disposition": "attachment; filename=\"object.txt\"",
+		"Cache-Control":       "no-cache",
+		"Expires":             "Thu, 01 Dec 2094 16:00:00 GMT",
+	}
+	tags := map[string]string{"project": "minio"}
+	_, err = drivers.CreateObject("bucket", "object", "text/plain", "", int64(len("hello world")),
+		bytes.NewBufferString("hello world"), ObjectAttributes{Metadata: metadata, Tags: tags})
+	c.Assert(err, check.IsNil)
+
+	objectMetadata, err := drivers.GetObjectMetadata("bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(objectMetadata.ContentType, check.Equals, "text/plain")
+	c.Assert(objectMetadata.Metadata, check.DeepEquals, metadata)
+	c.Assert(objectMetadata.Tags, check.DeepEquals, tags)
+
+	// copies keep the metadata unless it is replaced, tags are always kept
+	_, err = drivers.CopyObject("bucket", "object", "bucket", "copy", "", nil, nil)
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "copy")
+	c.Assert(err, check.IsNil)
+	c.Assert(objectMetadata.ContentType, check.Equals, "text/plain")
+	c.Assert(objectMetadata.Metadata, check.DeepEquals, metadata)
+	c.Assert(objectMetadata.Tags, check.DeepEquals, tags)
+
+	_, err = drivers.CopyObject("bucket", "object", "bucket", "replaced", "application/json",
+		map[string]string{"X-Amz-Meta-Owner": "someone"}, nil)
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "replaced")
+	c.Assert(err, check.IsNil)
+	c.Assert(objectMetadata.ContentType, check.Equals, "application/json")
+	c.Assert(objectMetadata.Metadata, check.DeepEquals, map[string]string{"X-Amz-Meta-Owner": "someone"})
+}
+
+func testObjectTags(c *check.C, create func() Driver) {
+	drivers := create()
+	err := drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+
+	_, err = drivers.CreateObject("bucket", "object", "", "", int64(len("hello world")),
+		bytes.NewBufferString("hello world"), ObjectAttributes{})
+	c.Assert(err, check.IsNil)
+
+	objectMetadata, err := drivers.GetObjectMetadata("bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(len(objectMetadata.Tags), check.Equals, 0)
+
+	tags := map[string]string{"team": "storage", "retention": "short"}
+	err = drivers.SetObjectTags("bucket", "object", tags)
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(objectMetadata.Tags, check.DeepEquals, tags)
+
+	// tags are copied along with the data
+	_, err = drivers.CopyObject("bucket", "object", "bucket", "copy", "", nil, nil)
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "copy")
+	c.Assert(err, check.IsNil)
+	c.Assert(objectMetadata.Tags, check.DeepEquals, tags)
+
+	err = drivers.SetObjectTags("bucket", "object", nil)
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(len(objectMetadata.Tags), check.Equals, 0)
+
+	var byteBuffer bytes.Buffer
+	_, err = drivers.GetObject(&byteBuffer, "bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(byteBuffer.String(), check.Equals, "hello world")
+
+	err = drivers.SetObjectTags("bucket", "nonexistant", tags)
+	switch iodine.ToError(err).(type) {
+	case ObjectNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "ObjectNotFound")
+		}
+	}
+}
+
+func testObjectACL(c *check.C, create func() Driver) {
+	drivers := create()
+	err := drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+
+	_, err = drivers.CreateObject("bucket", "object", "", "", int64(len("hello world")),
+		bytes.NewBufferString("hello world"), ObjectAttributes{})
+	c.Assert(err, check.IsNil)
+
+	objectMetadata, err := drivers.GetObjectMetadata("bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(len(objectMetadata.ACL), check.Equals, 0)
+
+	grants := []Grant{
+		{GranteeID: "minio", Permission: "FULL_CONTROL"},
+		{GranteeURI: "http://acs.amazonaws.com/groups/global/AllUsers", Permission: "READ"},
+	}
+	err = drivers.SetObjectACL("bucket", "object", grants)
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(objectMetadata.ACL, check.DeepEquals, grants)
+
+	// a copy gets the grants it is created with rather than those of the source
+	_, err = drivers.CopyObject("bucket", "object", "bucket", "copy", "", nil, nil)
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "copy")
+	c.Assert(err, check.IsNil)
+	c.Assert(len(objectMetadata.ACL), check.Equals, 0)
+
+	_, err = drivers.CopyObject("bucket", "object", "bucket", "publiccopy", "", nil, grants[1:])
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "publiccopy")
+	c.Assert(err, check.IsNil)
+	c.Assert(objectMetadata.ACL, check.DeepEquals, grants[1:])
+
+	// grants of a new object are written along with its data
+	_, err = drivers.CreateObject("bucket", "public", "", "", int64(len("hello world")),
+		bytes.NewBufferString("hello world"), ObjectAttributes{ACL: grants})
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "public")
+	c.Assert(err, check.IsNil)
+	c.Assert(objectMetadata.ACL, check.DeepEquals, grants)
+
+	err = drivers.SetObjectACL("bucket", "object", nil)
+	c.Assert(err, check.IsNil)
+	objectMetadata, err = drivers.GetObjectMetadata("bucket", "object")
+	c.Assert(err, check.IsNil)
+	c.Assert(len(objectMetadata.ACL), check.Equals, 0)
+
+	err = drivers.SetObjectACL("bucket", "nonexistant", grants)
+	switch iodine.ToError(err).(type) {
+	case ObjectNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "ObjectNotFound")
+		}
+	}
+}
+
+func testDeleteBucket(c *check.C, create func() Driver) {
+	drivers := create()
+	err := drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+
+	_, err = drivers.CreateObject("bucket", "dir1/object", "", "", int64(len("hello world")),
+		bytes.NewBufferString("hello world"), ObjectAttributes{})
+	c.Assert(err, check.IsNil)
+
+	err = drivers.DeleteBucket("bucket")
+	switch iodine.ToError(err).(type) {
+	case BucketNotEmpty:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "BucketNotEmpty")
+		}
+	}
+
+	err = drivers.DeleteObject("bucket", "dir1/object")
+	c.Assert(err, check.IsNil)
+
+	err = drivers.DeleteBucket("bucket")
+	c.Assert(err, check.IsNil)
+
+	buckets, err := drivers.ListBuckets()
+	c.Assert(err, check.IsNil)
+	c.Assert(len(buckets), check.Equals, 0)
+
+	err = drivers.DeleteBucket("bucket")
+	switch iodine.ToError(err).(type) {
+	case BucketNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "BucketNotFound")
+		}
+	}
+
+	// bucket can be recreated once deleted
+	err = drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+
+	// in-progress multipart uploads keep a bucket from being deleted
+	if reflect.TypeOf(drivers).String() == "*donut.donutDriver" {
+		return
+	}
+	uploadID, err := drivers.NewMultipartUpload("bucket", "key", "", ObjectAttributes{})
+	c.Assert(err, check.IsNil)
+
+	err = drivers.DeleteBucket("bucket")
+	switch iodine.ToError(err).(type) {
+	case BucketNotEmpty:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "BucketNotEmpty")
+		}
+	}
+
+	err = drivers.AbortMultipartUpload("bucket", "key", uploadID)
+	c.Assert(err, check.IsNil)
+
+	err = drivers.DeleteBucket("bucket")
+	c.Assert(err, check.IsNil)
+}
+
+func testBucketResources(c *check.C, create func() Driver) {
+	drivers := create()
+	err := drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+
+	_, err = drivers.GetBucketResource("bucket", "policy")
+	switch iodine.ToError(err).(type) {
+	case BucketResourceNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "BucketResourceNotFound")
+		}
+	}
+
+	err = drivers.SetBucketResource("bucket", "policy", []byte("{\"Version\": \"2012-10-17\"}"))
+	c.Assert(err, check.IsNil)
+	err = drivers.SetBucketResource("bucket", "cors", []byte("<CORSConfiguration/>"))
+	c.Assert(err, check.IsNil)
+	err = drivers.SetBucketResource("bucket", "policy", []byte("{\"Version\": \"2008-10-17\"}"))
+	c.Assert(err, check.IsNil)
+
+	data, err := drivers.GetBucketResource("bucket", "policy")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "{\"Version\": \"2008-10-17\"}")
+	data, err = drivers.GetBucketResource("bucket", "cors")
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "<CORSConfiguration/>")
+
+	// resources do not show up as objects or buckets
+	objects, _, err := drivers.ListObjects("bucket", BucketResourcesMetadata{Maxkeys: 1000})
+	c.Assert(err, check.IsNil)
+	c.Assert(len(objects), check.Equals, 0)
+	buckets, err := drivers.ListBuckets()
+	c.Assert(err, check.IsNil)
+	c.Assert(len(buckets), check.Equals, 1)
+
+	err = drivers.DeleteBucketResource("bucket", "policy")
+	c.Assert(err, check.IsNil)
+	_, err = drivers.GetBucketResource("bucket", "policy")
+	switch iodine.ToError(err).(type) {
+	case BucketResourceNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "BucketResourceNotFound")
+		}
+	}
+	err = drivers.DeleteBucketResource("bucket", "policy")
+	switch iodine.ToError(err).(type) {
+	case BucketResourceNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "BucketResourceNotFound")
+		}
+	}
+
+	// resources go away along with the bucket
+	err = drivers.DeleteBucket("bucket")
+	c.Assert(err, check.IsNil)
+	err = drivers.CreateBucket("bucket", "")
+	c.Assert(err, check.IsNil)
+	_, err = drivers.GetBucketResource("bucket", "cors")
+	switch iodine.ToError(err).(type) {
+	case BucketResourceNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "BucketResourceNotFound")
+		}
+	}
+
+	err = drivers.SetBucketResource("nonexistantbucket", "policy", []byte("{}"))
+	switch iodine.ToError(err).(type) {
+	case BucketNotFound:
+	default:
+		{
+			// force a failure with a line number
+			c.Assert(err, check.Equals, "BucketNotFound")
+		}
+	}
+}
+
 func testContentMd5Set(c *check.C, create func() Driver) {
 	drivers := create()
 	err := drivers.CreateBucket("bucket", "")
@@ -524,12 +1132,12 @@ func testContentMd5Set(c *check.C, create func() Driver) {
 
 	// test md5 invalid
 	badmd5Sum := "NWJiZjVhNTIzMjhlNzQzOWFlNmU3MTlkZmU3MTIyMDA"
-	calculatedmd5sum, err := drivers.CreateObject("bucket", "one", "", badmd5Sum, int64(len("one")), bytes.NewBufferString("one"))
+	calculatedmd5sum, err := drivers.CreateObject("bucket", "one", "", badmd5Sum, int64(len("one")), bytes.NewBufferString("one"), ObjectAttributes{})
 	c.Assert(err, check.Not(check.IsNil))
-	c.Assert(calculatedmd5sum, check.Not(check.Equals), badmd5Sum)
+	c.Assert(calculatedmd5sum.Md5, check.Not(check.Equals), badmd5Sum)
 
 	goodmd5sum := "NWJiZjVhNTIzMjhlNzQzOWFlNmU3MTlkZmU3MTIyMDA="
-	calculatedmd5sum, err = drivers.CreateObject("bucket", "two", "", goodmd5sum, int64(len("one")), bytes.NewBufferString("one"))
+	calculatedmd5sum, err = drivers.CreateObject("bucket", "two", "", goodmd5sum, int64(len("one")), bytes.NewBufferString("one"), ObjectAttributes{})
 	c.Assert(err, check.IsNil)
-	c.Assert(calculatedmd5sum, check.Equals, goodmd5sum)
+	c.Assert(calculatedmd5sum.Md5, check.Equals, goodmd5sum)
 }
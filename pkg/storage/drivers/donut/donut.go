@@ -216,6 +216,101 @@ func (d donutDriver) SetBucketMetadata(bucketName, acl string) error {
 	return nil
 }
 
+// bucketResourcePrefix - prefix of the donut bucket metadata keys holding bucket resources
+const bucketResourcePrefix = "resource."
+
+// GetBucketResource retrieves a resource kept in the bucket's metadata
+func (d donutDriver) GetBucketResource(bucketName, resource string) ([]byte, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if d.donut == nil {
+		return nil, iodine.New(drivers.InternalError{}, nil)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return nil, iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, nil)
+	}
+	metadata, err := d.donut.GetBucketMetadata(bucketName)
+	if err != nil {
+		return nil, iodine.New(drivers.BucketNotFound{Bucket: bucketName}, nil)
+	}
+	data, ok := metadata.Metadata[bucketResourcePrefix+resource]
+	if !ok {
+		return nil, iodine.New(drivers.BucketResourceNotFound{Bucket: bucketName, Resource: resource}, nil)
+	}
+	return []byte(data), nil
+}
+
+// SetBucketResource sets a resource in the bucket's metadata
+func (d donutDriver) SetBucketResource(bucketName, resource string, data []byte) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.donut == nil {
+		return iodine.New(drivers.InternalError{}, nil)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, nil)
+	}
+	// donut drops keys with empty values, setting an empty resource is the same as deleting it
+	bucketMetadata := make(map[string]string)
+	bucketMetadata[bucketResourcePrefix+resource] = string(data)
+	if err := d.donut.SetBucketMetadata(bucketName, bucketMetadata); err != nil {
+		switch iodine.ToError(err).(type) {
+		case donut.BucketNotFound:
+			return iodine.New(drivers.BucketNotFound{Bucket: bucketName}, nil)
+		}
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// DeleteBucketResource removes a resource from the bucket's metadata
+func (d donutDriver) DeleteBucketResource(bucketName, resource string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.donut == nil {
+		return iodine.New(drivers.InternalError{}, nil)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, nil)
+	}
+	metadata, err := d.donut.GetBucketMetadata(bucketName)
+	if err != nil {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucketName}, nil)
+	}
+	if _, ok := metadata.Metadata[bucketResourcePrefix+resource]; !ok {
+		return iodine.New(drivers.BucketResourceNotFound{Bucket: bucketName, Resource: resource}, nil)
+	}
+	// an empty value removes the key from donut bucket metadata
+	bucketMetadata := make(map[string]string)
+	bucketMetadata[bucketResourcePrefix+resource] = ""
+	if err := d.donut.SetBucketMetadata(bucketName, bucketMetadata); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// DeleteBucket deletes a bucket, only if it is empty
+func (d donutDriver) DeleteBucket(bucketName string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.donut == nil {
+		return iodine.New(drivers.InternalError{}, nil)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, nil)
+	}
+	if err := d.donut.DeleteBucket(bucketName); err != nil {
+		switch iodine.ToError(err).(type) {
+		case donut.BucketNotFound:
+			return iodine.New(drivers.BucketNotFound{Bucket: bucketName}, nil)
+		case donut.BucketNotEmpty:
+			return iodine.New(drivers.BucketNotEmpty{Bucket: bucketName}, nil)
+		}
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
 // GetObject retrieves an object and writes it to a writer
 func (d donutDriver) GetObject(target io.Writer, bucketName, objectName string) (int64, error) {
 	d.lock.RLock()
@@ -323,10 +418,33 @@ func (d donutDriver) GetObjectMetadata(bucketName, objectName string) (drivers.O
 		Created:     metadata.Created,
 		Md5:         metadata.MD5Sum,
 		Size:        metadata.Size,
+		Metadata:    getUserMetadata(metadata.Metadata),
+		Tags:        metadata.Tags,
+		ACL:         getDriverGrants(metadata.ACL),
+		VersionID:   metadata.VersionID,
+		IsLatest:    true,
 	}
 	return objectMetadata, nil
 }
 
+// donut keeps its own contentType and contentLength entries alongside the user metadata of an object
+var donutMetadataKeys = map[string]bool{
+	"contentType":   true,
+	"contentLength": true,
+}
+
+// getUserMetadata - metadata stored along with an object, without the entries reserved by donut
+func getUserMetadata(metadata map[string]string) map[string]string {
+	userMetadata := make(map[string]string)
+	for key, value := range metadata {
+		if donutMetadataKeys[key] {
+			continue
+		}
+		userMetadata[key] = value
+	}
+	return userMetadata
+}
+
 type byObjectKey []drivers.ObjectMetadata
 
 func (b byObjectKey) Len() int           { return len(b) }
@@ -377,7 +495,7 @@ func (d donutDriver) ListObjects(bucketName string, resources drivers.BucketReso
 }
 
 // CreateObject creates a new object
-func (d donutDriver) CreateObject(bucketName, objectName, contentType, expectedMD5Sum string, size int64, reader io.Reader) (string, error) {
+func (d donutDriver) CreateObject(bucketName, objectName, contentType, expectedMD5Sum string, size int64, reader io.Reader, attributes drivers.ObjectAttributes) (drivers.ObjectMetadata, error) {
 	d.lock.Lock()
 	defer d.lock.Unlock()
 	errParams := map[string]string{
@@ -386,40 +504,561 @@ func (d donutDriver) CreateObject(bucketName, objectName, contentType, expectedM
 		"contentType": contentType,
 	}
 	if d.donut == nil {
-		return "", iodine.New(drivers.InternalError{}, errParams)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.InternalError{}, errParams)
 	}
 	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
-		return "", iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, nil)
 	}
 	if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
-		return "", iodine.New(drivers.ObjectNameInvalid{Object: objectName}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Object: objectName}, nil)
 	}
 	if strings.TrimSpace(contentType) == "" {
 		contentType = "application/octet-stream"
 	}
 	metadata := make(map[string]string)
+	for key, value := range getUserMetadata(attributes.Metadata) {
+		metadata[key] = value
+	}
 	metadata["contentType"] = strings.TrimSpace(contentType)
 	metadata["contentLength"] = strconv.FormatInt(size, 10)
 
 	if strings.TrimSpace(expectedMD5Sum) != "" {
 		expectedMD5SumBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(expectedMD5Sum))
 		if err != nil {
-			return "", iodine.New(err, nil)
+			return drivers.ObjectMetadata{}, iodine.New(err, nil)
 		}
 		expectedMD5Sum = hex.EncodeToString(expectedMD5SumBytes)
 	}
-	calculatedMD5Sum, err := d.donut.PutObject(bucketName, objectName, expectedMD5Sum, ioutil.NopCloser(reader), metadata)
+	objMetadata, err := d.donut.PutObject(bucketName, objectName, expectedMD5Sum, ioutil.NopCloser(reader), metadata, attributes.Tags, getDonutGrants(attributes.ACL))
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, errParams)
+	}
+	return getVersionMetadata(bucketName, objectName, objMetadata), nil
+}
+
+// CopyObject re-encodes the source object into a new object, an empty contentType keeps the content type and metadata of the source
+func (d donutDriver) CopyObject(sourceBucketName, sourceObjectName, bucketName, objectName, contentType string, objectMetadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.copyObject(sourceBucketName, sourceObjectName, "", bucketName, objectName, contentType, objectMetadata, grants)
+}
+
+// CopyObjectVersion re-encodes a version of the source object into a new object, an empty contentType keeps the content type
+// and metadata of the source
+func (d donutDriver) CopyObjectVersion(sourceBucketName, sourceObjectName, sourceVersionID, bucketName, objectName, contentType string, objectMetadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.copyObject(sourceBucketName, sourceObjectName, sourceVersionID, bucketName, objectName, contentType, objectMetadata, grants)
+}
+
+// copyObject copies a version of the source object, an empty sourceVersionID copies the current object. Caller holds the lock
+func (d donutDriver) copyObject(sourceBucketName, sourceObjectName, sourceVersionID, bucketName, objectName, contentType string, objectMetadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	errParams := map[string]string{
+		"sourceBucketName": sourceBucketName,
+		"sourceObjectName": sourceObjectName,
+		"sourceVersionID":  sourceVersionID,
+		"bucketName":       bucketName,
+		"objectName":       objectName,
+		"contentType":      contentType,
+	}
+	if d.donut == nil {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(sourceBucketName) || strings.Contains(sourceBucketName, ".") {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: sourceBucketName}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	if !drivers.IsValidObjectName(sourceObjectName) || strings.TrimSpace(sourceObjectName) == "" {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Object: sourceObjectName}, errParams)
+	}
+	if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Object: objectName}, errParams)
+	}
+	sourceMetadata, reader, size, err := d.getCopySource(sourceBucketName, sourceObjectName, sourceVersionID)
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, errParams)
+	}
+	defer reader.Close()
+	if strings.TrimSpace(contentType) == "" {
+		contentType = sourceMetadata.Metadata["contentType"]
+		objectMetadata = sourceMetadata.Metadata
+	}
+	metadata := make(map[string]string)
+	for key, value := range getUserMetadata(objectMetadata) {
+		metadata[key] = value
+	}
+	metadata["contentType"] = strings.TrimSpace(contentType)
+	metadata["contentLength"] = strconv.FormatInt(size, 10)
+
+	if sourceBucketName == bucketName && sourceObjectName == objectName {
+		bucketMetadata, err := d.donut.GetBucketMetadata(bucketName)
+		if err != nil {
+			return drivers.ObjectMetadata{}, iodine.New(err, errParams)
+		}
+		// unless the null version is kept as an earlier version, writing the copy would overwrite the data being read
+		if sourceMetadata.IsLatest && sourceMetadata.VersionID == "" && bucketMetadata.Versioning != drivers.VersioningEnabled {
+			copyMetadata, err := d.donut.ReplaceObjectMetadata(bucketName, objectName, metadata, getDonutGrants(grants))
+			if err != nil {
+				return drivers.ObjectMetadata{}, iodine.New(err, errParams)
+			}
+			return getVersionMetadata(bucketName, objectName, copyMetadata), nil
+		}
+	}
+
+	// verify the copy against the md5sum of the source, tags are copied along with the data
+	copyMetadata, err := d.donut.PutObject(bucketName, objectName, sourceMetadata.MD5Sum, reader, metadata, sourceMetadata.Tags, getDonutGrants(grants))
+	if err != nil {
+		switch iodine.ToError(err).(type) {
+		case donut.BucketNotFound:
+			return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNotFound{Bucket: bucketName}, errParams)
+		case donut.ObjectExists:
+			return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectExists{Bucket: bucketName, Object: objectName}, errParams)
+		}
+		return drivers.ObjectMetadata{}, iodine.New(err, errParams)
+	}
+	return drivers.ObjectMetadata{
+		Bucket: bucketName,
+		Key:    objectName,
+
+		ContentType: copyMetadata.Metadata["contentType"],
+		Created:     copyMetadata.Created,
+		Md5:         copyMetadata.MD5Sum,
+		Size:        copyMetadata.Size,
+		Metadata:    getUserMetadata(copyMetadata.Metadata),
+		Tags:        copyMetadata.Tags,
+		ACL:         getDriverGrants(copyMetadata.ACL),
+		VersionID:   copyMetadata.VersionID,
+		IsLatest:    true,
+	}, nil
+}
+
+// getCopySource opens the version of an object a copy is made from, an empty versionID opens the current object
+func (d donutDriver) getCopySource(bucketName, objectName, versionID string) (donut.ObjectMetadata, io.ReadCloser, int64, error) {
+	if versionID != "" {
+		sourceMetadata, err := d.donut.GetObjectVersionMetadata(bucketName, objectName, versionID)
+		if err != nil {
+			return donut.ObjectMetadata{}, nil, 0, iodine.New(getVersionError(err, bucketName, objectName, versionID), nil)
+		}
+		reader, size, err := d.donut.GetObjectVersion(bucketName, objectName, versionID)
+		if err != nil {
+			return donut.ObjectMetadata{}, nil, 0, iodine.New(getVersionError(err, bucketName, objectName, versionID), nil)
+		}
+		return sourceMetadata, reader, size, nil
+	}
+	sourceMetadata, err := d.donut.GetObjectMetadata(bucketName, objectName)
+	if err != nil {
+		switch iodine.ToError(err).(type) {
+		case donut.BucketNotFound:
+			return donut.ObjectMetadata{}, nil, 0, iodine.New(drivers.BucketNotFound{Bucket: bucketName}, nil)
+		}
+		return donut.ObjectMetadata{}, nil, 0, iodine.New(drivers.ObjectNotFound{
+			Bucket: bucketName,
+			Object: objectName,
+		}, nil)
+	}
+	reader, size, err := d.donut.GetObject(bucketName, objectName)
+	if err != nil {
+		return donut.ObjectMetadata{}, nil, 0, iodine.New(drivers.ObjectNotFound{
+			Bucket: bucketName,
+			Object: objectName,
+		}, nil)
+	}
+	return sourceMetadata, reader, size, nil
+}
+
+// DeleteObject deletes an object
+func (d donutDriver) DeleteObject(bucketName, objectName string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	errParams := map[string]string{
+		"bucketName": bucketName,
+		"objectName": objectName,
+	}
+	if d.donut == nil {
+		return iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
+		return iodine.New(drivers.ObjectNameInvalid{Object: objectName}, errParams)
+	}
+	if err := d.donut.DeleteObject(bucketName, objectName); err != nil {
+		switch iodine.ToError(err).(type) {
+		case donut.BucketNotFound:
+			return iodine.New(drivers.BucketNotFound{Bucket: bucketName}, errParams)
+		case donut.ObjectNotFound:
+			return iodine.New(drivers.ObjectNotFound{Bucket: bucketName, Object: objectName}, errParams)
+		}
+		return iodine.New(err, errParams)
+	}
+	return nil
+}
+
+// SetObjectTags replaces the tags of an object
+func (d donutDriver) SetObjectTags(bucketName, objectName string, tags map[string]string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	errParams := map[string]string{
+		"bucketName": bucketName,
+		"objectName": objectName,
+	}
+	if d.donut == nil {
+		return iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
+		return iodine.New(drivers.ObjectNameInvalid{Object: objectName}, errParams)
+	}
+	if err := d.donut.SetObjectTags(bucketName, objectName, tags); err != nil {
+		switch iodine.ToError(err).(type) {
+		case donut.BucketNotFound:
+			return iodine.New(drivers.BucketNotFound{Bucket: bucketName}, errParams)
+		case donut.ObjectNotFound:
+			return iodine.New(drivers.ObjectNotFound{Bucket: bucketName, Object: objectName}, errParams)
+		}
+		return iodine.New(err, errParams)
+	}
+	return nil
+}
+
+// SetObjectACL replaces the ACL grants of an object
+func (d donutDriver) SetObjectACL(bucketName, objectName string, grants []drivers.Grant) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	errParams := map[string]string{
+		"bucketName": bucketName,
+		"objectName": objectName,
+	}
+	if d.donut == nil {
+		return iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
+		return iodine.New(drivers.ObjectNameInvalid{Object: objectName}, errParams)
+	}
+	if err := d.donut.SetObjectACL(bucketName, objectName, getDonutGrants(grants)); err != nil {
+		switch iodine.ToError(err).(type) {
+		case donut.BucketNotFound:
+			return iodine.New(drivers.BucketNotFound{Bucket: bucketName}, errParams)
+		case donut.ObjectNotFound:
+			return iodine.New(drivers.ObjectNotFound{Bucket: bucketName, Object: objectName}, errParams)
+		}
+		return iodine.New(err, errParams)
+	}
+	return nil
+}
+
+// getDonutGrants - object ACL grants as donut keeps them
+func getDonutGrants(grants []drivers.Grant) []donut.Grant {
+	var donutGrants []donut.Grant
+	for _, grant := range grants {
+		donutGrants = append(donutGrants, donut.Grant{
+			GranteeID:  grant.GranteeID,
+			GranteeURI: grant.GranteeURI,
+			Permission: grant.Permission,
+		})
+	}
+	return donutGrants
+}
+
+// getDriverGrants - object ACL grants kept by donut
+func getDriverGrants(donutGrants []donut.Grant) []drivers.Grant {
+	var grants []drivers.Grant
+	for _, grant := range donutGrants {
+		grants = append(grants, drivers.Grant{
+			GranteeID:  grant.GranteeID,
+			GranteeURI: grant.GranteeURI,
+			Permission: grant.Permission,
+		})
+	}
+	return grants
+}
+
+// DeleteObjects deletes several objects of a bucket, errors are returned per object name
+func (d donutDriver) DeleteObjects(bucketName string, objectNames []string) (map[string]error, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	errParams := map[string]string{
+		"bucketName": bucketName,
+	}
+	if d.donut == nil {
+		return nil, iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return nil, iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	errs := make(map[string]error)
+	var validObjectNames []string
+	for _, objectName := range objectNames {
+		if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
+			errs[objectName] = iodine.New(drivers.ObjectNameInvalid{Object: objectName}, errParams)
+			continue
+		}
+		validObjectNames = append(validObjectNames, objectName)
+	}
+	donutErrs, err := d.donut.DeleteObjects(bucketName, validObjectNames)
+	if err != nil {
+		switch iodine.ToError(err).(type) {
+		case donut.BucketNotFound:
+			return nil, iodine.New(drivers.BucketNotFound{Bucket: bucketName}, errParams)
+		}
+		return nil, iodine.New(err, errParams)
+	}
+	for objectName, err := range donutErrs {
+		switch iodine.ToError(err).(type) {
+		case donut.ObjectNotFound:
+			errs[objectName] = iodine.New(drivers.ObjectNotFound{Bucket: bucketName, Object: objectName}, errParams)
+		default:
+			errs[objectName] = iodine.New(err, errParams)
+		}
+	}
+	return errs, nil
+}
+
+// GetBucketVersioning retrieves the versioning status kept in the bucket's metadata
+func (d donutDriver) GetBucketVersioning(bucketName string) (string, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	if d.donut == nil {
+		return "", iodine.New(drivers.InternalError{}, nil)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return "", iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, nil)
+	}
+	metadata, err := d.donut.GetBucketMetadata(bucketName)
+	if err != nil {
+		return "", iodine.New(drivers.BucketNotFound{Bucket: bucketName}, nil)
+	}
+	return metadata.Versioning, nil
+}
+
+// SetBucketVersioning sets the versioning status in the bucket's metadata
+func (d donutDriver) SetBucketVersioning(bucketName, status string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.donut == nil {
+		return iodine.New(drivers.InternalError{}, nil)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, nil)
+	}
+	bucketMetadata := make(map[string]string)
+	bucketMetadata["versioning"] = status
+	if err := d.donut.SetBucketMetadata(bucketName, bucketMetadata); err != nil {
+		switch iodine.ToError(err).(type) {
+		case donut.BucketNotFound:
+			return iodine.New(drivers.BucketNotFound{Bucket: bucketName}, nil)
+		}
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// getVersionMetadata - object metadata of a donut object version
+func getVersionMetadata(bucketName, objectName string, metadata donut.ObjectMetadata) drivers.ObjectMetadata {
+	objectMetadata := drivers.ObjectMetadata{
+		Bucket: bucketName,
+		Key:    objectName,
+
+		Created:        metadata.Created,
+		VersionID:      metadata.VersionID,
+		IsLatest:       metadata.IsLatest,
+		IsDeleteMarker: metadata.DeleteMarker,
+	}
+	if metadata.DeleteMarker {
+		return objectMetadata
+	}
+	objectMetadata.ContentType = metadata.Metadata["contentType"]
+	objectMetadata.Md5 = metadata.MD5Sum
+	objectMetadata.Size = metadata.Size
+	objectMetadata.Metadata = getUserMetadata(metadata.Metadata)
+	objectMetadata.Tags = metadata.Tags
+	objectMetadata.ACL = getDriverGrants(metadata.ACL)
+	return objectMetadata
+}
+
+// getVersionError - driver error of a donut object version error
+func getVersionError(err error, bucketName, objectName, versionID string) error {
+	switch iodine.ToError(err).(type) {
+	case donut.BucketNotFound:
+		return drivers.BucketNotFound{Bucket: bucketName}
+	case donut.ObjectNotFound:
+		return drivers.ObjectNotFound{Bucket: bucketName, Object: objectName}
+	case donut.VersionNotFound:
+		return drivers.ObjectVersionNotFound{
+			GenericObjectError: drivers.GenericObjectError{Bucket: bucketName, Object: objectName},
+			VersionID:          versionID,
+		}
+	}
+	return err
+}
+
+// GetObjectVersion retrieves a version of an object and writes it to a writer
+func (d donutDriver) GetObjectVersion(target io.Writer, bucketName, objectName, versionID string) (int64, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	errParams := map[string]string{
+		"bucketName": bucketName,
+		"objectName": objectName,
+		"versionID":  versionID,
+	}
+	if d.donut == nil {
+		return 0, iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return 0, iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
+		return 0, iodine.New(drivers.ObjectNameInvalid{Object: objectName}, errParams)
+	}
+	reader, size, err := d.donut.GetObjectVersion(bucketName, objectName, versionID)
+	if err != nil {
+		return 0, iodine.New(getVersionError(err, bucketName, objectName, versionID), errParams)
+	}
+	defer reader.Close()
+	n, err := io.CopyN(target, reader, size)
+	if err != nil {
+		return n, iodine.New(err, errParams)
+	}
+	return n, nil
+}
+
+// GetPartialObjectVersion retrieves a range of a version of an object
+func (d donutDriver) GetPartialObjectVersion(target io.Writer, bucketName, objectName, versionID string, start, length int64) (int64, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	errParams := map[string]string{
+		"bucketName": bucketName,
+		"objectName": objectName,
+		"versionID":  versionID,
+		"start":      strconv.FormatInt(start, 10),
+		"length":     strconv.FormatInt(length, 10),
+	}
+	if d.donut == nil {
+		return 0, iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return 0, iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
+		return 0, iodine.New(drivers.ObjectNameInvalid{Object: objectName}, errParams)
+	}
+	if start < 0 {
+		return 0, iodine.New(drivers.InvalidRange{
+			Start:  start,
+			Length: length,
+		}, errParams)
+	}
+	reader, size, err := d.donut.GetObjectVersion(bucketName, objectName, versionID)
+	if err != nil {
+		return 0, iodine.New(getVersionError(err, bucketName, objectName, versionID), errParams)
+	}
+	defer reader.Close()
+	if start > size || (start+length-1) > size {
+		return 0, iodine.New(drivers.InvalidRange{
+			Start:  start,
+			Length: length,
+		}, errParams)
+	}
+	if _, err := io.CopyN(ioutil.Discard, reader, start); err != nil {
+		return 0, iodine.New(err, errParams)
+	}
+	n, err := io.CopyN(target, reader, length)
+	if err != nil {
+		return n, iodine.New(err, errParams)
+	}
+	return n, nil
+}
+
+// GetObjectVersionMetadata retrieves the metadata of a version of an object
+func (d donutDriver) GetObjectVersionMetadata(bucketName, objectName, versionID string) (drivers.ObjectMetadata, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	errParams := map[string]string{
+		"bucketName": bucketName,
+		"objectName": objectName,
+		"versionID":  versionID,
+	}
+	if d.donut == nil {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Object: objectName}, errParams)
+	}
+	metadata, err := d.donut.GetObjectVersionMetadata(bucketName, objectName, versionID)
 	if err != nil {
-		return "", iodine.New(err, errParams)
+		return drivers.ObjectMetadata{}, iodine.New(getVersionError(err, bucketName, objectName, versionID), errParams)
 	}
-	return calculatedMD5Sum, nil
+	return getVersionMetadata(bucketName, objectName, metadata), nil
+}
+
+// DeleteObjectVersion deletes a version of an object for good
+func (d donutDriver) DeleteObjectVersion(bucketName, objectName, versionID string) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	errParams := map[string]string{
+		"bucketName": bucketName,
+		"objectName": objectName,
+		"versionID":  versionID,
+	}
+	if d.donut == nil {
+		return iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	if !drivers.IsValidObjectName(objectName) || strings.TrimSpace(objectName) == "" {
+		return iodine.New(drivers.ObjectNameInvalid{Object: objectName}, errParams)
+	}
+	if err := d.donut.DeleteObjectVersion(bucketName, objectName, versionID); err != nil {
+		return iodine.New(getVersionError(err, bucketName, objectName, versionID), errParams)
+	}
+	return nil
+}
+
+// ListObjectVersions - returns list of object versions
+func (d donutDriver) ListObjectVersions(bucketName string, resources drivers.BucketResourcesMetadata) ([]drivers.ObjectMetadata, drivers.BucketResourcesMetadata, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	errParams := map[string]string{
+		"bucketName": bucketName,
+	}
+	if d.donut == nil {
+		return nil, drivers.BucketResourcesMetadata{}, iodine.New(drivers.InternalError{}, errParams)
+	}
+	if !drivers.IsValidBucket(bucketName) || strings.Contains(bucketName, ".") {
+		return nil, drivers.BucketResourcesMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: bucketName}, errParams)
+	}
+	if !drivers.IsValidObjectName(resources.Prefix) {
+		return nil, drivers.BucketResourcesMetadata{}, iodine.New(drivers.ObjectNameInvalid{Object: resources.Prefix}, errParams)
+	}
+	versions, err := d.donut.ListObjectVersions(bucketName, resources.Prefix)
+	if err != nil {
+		return nil, drivers.BucketResourcesMetadata{}, iodine.New(getVersionError(err, bucketName, "", ""), errParams)
+	}
+	var results []drivers.ObjectMetadata
+	for _, version := range versions {
+		results = append(results, getVersionMetadata(bucketName, version.Object, version))
+	}
+	results, resources = drivers.FilterObjectVersions(results, resources)
+	return results, resources, nil
 }
 
 func (d donutDriver) ListMultipartUploads(bucket string, resources drivers.BucketMultipartResourcesMetadata) (drivers.BucketMultipartResourcesMetadata, error) {
 	return drivers.BucketMultipartResourcesMetadata{}, iodine.New(drivers.APINotImplemented{API: "ListMultipartUploads"}, nil)
 }
 
-func (d donutDriver) NewMultipartUpload(bucket, key, contentType string) (string, error) {
+func (d donutDriver) NewMultipartUpload(bucket, key, contentType string, attributes drivers.ObjectAttributes) (string, error) {
 	return "", iodine.New(drivers.APINotImplemented{API: "NewMultipartUpload"}, nil)
 }
 
@@ -427,8 +1066,8 @@ func (d donutDriver) CreateObjectPart(bucket, key, uploadID string, partID int,
 	return "", iodine.New(drivers.APINotImplemented{API: "CreateObjectPart"}, nil)
 }
 
-func (d donutDriver) CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (string, error) {
-	return "", iodine.New(drivers.APINotImplemented{API: "CompleteMultipartUpload"}, nil)
+func (d donutDriver) CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (drivers.ObjectMetadata, error) {
+	return drivers.ObjectMetadata{}, iodine.New(drivers.APINotImplemented{API: "CompleteMultipartUpload"}, nil)
 }
 
 func (d donutDriver) ListObjectParts(bucket, key string, resources drivers.ObjectResourcesMetadata) (drivers.ObjectResourcesMetadata, error) {
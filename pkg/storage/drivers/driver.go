@@ -31,20 +31,47 @@ type Driver interface {
 	CreateBucket(bucket, acl string) error
 	GetBucketMetadata(bucket string) (BucketMetadata, error)
 	SetBucketMetadata(bucket, acl string) error
+	DeleteBucket(bucket string) error
+
+	// Bucket Resource Operations, resources are documents such as the bucket policy kept along with a bucket
+	GetBucketResource(bucket, resource string) ([]byte, error)
+	SetBucketResource(bucket, resource string, data []byte) error
+	DeleteBucketResource(bucket, resource string) error
+
+	// Bucket Versioning Operations, the status is empty as long as versioning was never enabled on a bucket
+	GetBucketVersioning(bucket string) (string, error)
+	SetBucketVersioning(bucket, status string) error
 
 	// Object Operations
 	GetObject(w io.Writer, bucket, object string) (int64, error)
 	GetPartialObject(w io.Writer, bucket, object string, start, length int64) (int64, error)
 	GetObjectMetadata(bucket, key string) (ObjectMetadata, error)
 	ListObjects(bucket string, resources BucketResourcesMetadata) ([]ObjectMetadata, BucketResourcesMetadata, error)
-	CreateObject(bucket, key, contentType, md5sum string, size int64, data io.Reader) (string, error)
+	CreateObject(bucket, key, contentType, md5sum string, size int64, data io.Reader, attributes ObjectAttributes) (ObjectMetadata, error)
+	CopyObject(sourceBucket, sourceKey, bucket, key, contentType string, metadata map[string]string, grants []Grant) (ObjectMetadata, error)
+	DeleteObject(bucket, key string) error
+	DeleteObjects(bucket string, keys []string) (map[string]error, error)
+
+	// Object Tagging Operations, tags replace those of the latest version of an object, nil removes them
+	SetObjectTags(bucket, key string, tags map[string]string) error
+
+	// Object ACL Operations, grants replace those of the latest version of an object, nil restores the private default
+	SetObjectACL(bucket, key string, grants []Grant) error
+
+	// Object Version Operations, an empty versionID refers to the latest version which may be a delete marker
+	GetObjectVersion(w io.Writer, bucket, key, versionID string) (int64, error)
+	GetPartialObjectVersion(w io.Writer, bucket, key, versionID string, start, length int64) (int64, error)
+	GetObjectVersionMetadata(bucket, key, versionID string) (ObjectMetadata, error)
+	CopyObjectVersion(sourceBucket, sourceKey, sourceVersionID, bucket, key, contentType string, metadata map[string]string, grants []Grant) (ObjectMetadata, error)
+	DeleteObjectVersion(bucket, key, versionID string) error
+	ListObjectVersions(bucket string, resources BucketResourcesMetadata) ([]ObjectMetadata, BucketResourcesMetadata, error)
 
 	// Object Multipart Operations
 	ListMultipartUploads(bucket string, resources BucketMultipartResourcesMetadata) (BucketMultipartResourcesMetadata, error)
-	NewMultipartUpload(bucket, key, contentType string) (string, error)
+	NewMultipartUpload(bucket, key, contentType string, attributes ObjectAttributes) (string, error)
 	AbortMultipartUpload(bucket, key, UploadID string) error
 	CreateObjectPart(bucket, key, uploadID string, partID int, contentType string, md5sum string, size int64, data io.Reader) (string, error)
-	CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (string, error)
+	CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (ObjectMetadata, error)
 	ListObjectParts(bucket, key string, resources ObjectResourcesMetadata) (ObjectResourcesMetadata, error)
 }
 
@@ -53,9 +80,12 @@ type BucketACL string
 
 // different types of ACL's currently supported for buckets
 const (
-	BucketPrivate         = BucketACL("private")
-	BucketPublicRead      = BucketACL("public-read")
-	BucketPublicReadWrite = BucketACL("public-read-write")
+	BucketPrivate           = BucketACL("private")
+	BucketPublicRead        = BucketACL("public-read")
+	BucketPublicReadWrite   = BucketACL("public-read-write")
+	BucketAuthenticatedRead = BucketACL("authenticated-read")
+	BucketOwnerRead         = BucketACL("bucket-owner-read")
+	BucketOwnerFullControl  = BucketACL("bucket-owner-full-control")
 )
 
 func (b BucketACL) String() string {
@@ -77,6 +107,27 @@ func (b BucketACL) IsPublicReadWrite() bool {
 	return b == BucketACL("public-read-write")
 }
 
+// IsAuthenticatedRead - is acl AuthenticatedRead
+func (b BucketACL) IsAuthenticatedRead() bool {
+	return b == BucketACL("authenticated-read")
+}
+
+// Grant - permission an object ACL gives to a grantee, either a user by its canonical id or a group by its uri
+type Grant struct {
+	GranteeID  string
+	GranteeURI string
+	Permission string
+}
+
+// versioning status of a bucket
+const (
+	VersioningEnabled   = "Enabled"
+	VersioningSuspended = "Suspended"
+)
+
+// NullVersionID - version id of objects written while versioning was not enabled
+const NullVersionID = "null"
+
 // BucketMetadata - name and create date
 type BucketMetadata struct {
 	Name    string
@@ -93,6 +144,32 @@ type ObjectMetadata struct {
 	Created     time.Time
 	Md5         string
 	Size        int64
+
+	// user defined x-amz-meta-* and standard http headers stored along with the object, keyed by header name
+	Metadata map[string]string
+
+	// tags of the object, keyed by tag name
+	Tags map[string]string
+
+	// grants of the object ACL, nil for the private default
+	ACL []Grant
+
+	// version of the object, empty for the null version
+	VersionID      string
+	IsLatest       bool
+	IsDeleteMarker bool
+}
+
+// ObjectAttributes - metadata, tags and grants written along with the data of a new object
+type ObjectAttributes struct {
+	// user defined x-amz-meta-* and standard http headers, keyed by header name
+	Metadata map[string]string
+
+	// tags of the object, keyed by tag name
+	Tags map[string]string
+
+	// grants of the object ACL, nil for the private default
+	ACL []Grant
 }
 
 // FilterMode type
@@ -154,15 +231,17 @@ type BucketMultipartResourcesMetadata struct {
 
 // BucketResourcesMetadata - various types of bucket resources
 type BucketResourcesMetadata struct {
-	Prefix         string
-	Marker         string
-	NextMarker     string
-	Maxkeys        int
-	EncodingType   string
-	Delimiter      string
-	IsTruncated    bool
-	CommonPrefixes []string
-	Mode           FilterMode
+	Prefix              string
+	Marker              string
+	NextMarker          string
+	VersionIDMarker     string
+	NextVersionIDMarker string
+	Maxkeys             int
+	EncodingType        string
+	Delimiter           string
+	IsTruncated         bool
+	CommonPrefixes      []string
+	Mode                FilterMode
 }
 
 // GetMode - Populate filter mode
@@ -190,6 +269,12 @@ func IsValidBucketACL(acl string) bool {
 	case "public-read":
 		fallthrough
 	case "public-read-write":
+		fallthrough
+	case "authenticated-read":
+		fallthrough
+	case "bucket-owner-read":
+		fallthrough
+	case "bucket-owner-full-control":
 		return true
 	case "":
 		// by default its "private"
@@ -219,6 +304,76 @@ func (b BucketResourcesMetadata) IsDefault() bool {
 	return b.Mode == DefaultMode
 }
 
+// IsVersionMatch - version ids are equal, the null version is reported with an empty id
+func IsVersionMatch(versionID, otherVersionID string) bool {
+	if versionID == "" {
+		versionID = NullVersionID
+	}
+	if otherVersionID == "" {
+		otherVersionID = NullVersionID
+	}
+	return versionID == otherVersionID
+}
+
+// FilterObjectVersions - apply prefix, delimiter, markers and max keys of a versions listing,
+// versions are expected sorted by key and newest first for every key
+func FilterObjectVersions(versions []ObjectMetadata, resources BucketResourcesMetadata) ([]ObjectMetadata, BucketResourcesMetadata) {
+	maxkeys := resources.Maxkeys
+	if maxkeys <= 0 {
+		maxkeys = 1000
+	}
+	resources.CommonPrefixes = nil
+	resources.IsTruncated = false
+	resources.NextMarker = ""
+	resources.NextVersionIDMarker = ""
+	var results []ObjectMetadata
+	var lastKey, lastVersionID string
+	pastVersionIDMarker := false
+	for _, version := range versions {
+		if !strings.HasPrefix(version.Key, resources.Prefix) || version.Key < resources.Marker {
+			continue
+		}
+		// versions of the marker key are listed after the version id marker only
+		if version.Key == resources.Marker && !pastVersionIDMarker {
+			if resources.VersionIDMarker != "" && IsVersionMatch(version.VersionID, resources.VersionIDMarker) {
+				pastVersionIDMarker = true
+			}
+			continue
+		}
+		if resources.Delimiter != "" {
+			rest := strings.TrimPrefix(version.Key, resources.Prefix)
+			if i := strings.Index(rest, resources.Delimiter); i >= 0 {
+				commonPrefix := resources.Prefix + rest[:i+len(resources.Delimiter)]
+				// a common prefix is reported once, and not again on the page after it
+				if lastKey == commonPrefix || strings.HasPrefix(resources.Marker, commonPrefix) {
+					continue
+				}
+				if len(results)+len(resources.CommonPrefixes) >= maxkeys {
+					resources.IsTruncated = true
+					break
+				}
+				resources.CommonPrefixes = append(resources.CommonPrefixes, commonPrefix)
+				lastKey, lastVersionID = commonPrefix, ""
+				continue
+			}
+		}
+		if len(results)+len(resources.CommonPrefixes) >= maxkeys {
+			resources.IsTruncated = true
+			break
+		}
+		results = append(results, version)
+		lastKey, lastVersionID = version.Key, version.VersionID
+		if lastVersionID == "" {
+			lastVersionID = NullVersionID
+		}
+	}
+	if resources.IsTruncated {
+		resources.NextMarker = lastKey
+		resources.NextVersionIDMarker = lastVersionID
+	}
+	return results, resources
+}
+
 // IsValidBucket - verify bucket name in accordance with
 //  - http://docs.aws.amazon.com/AmazonS3/latest/dev/UsingBucket.html
 func IsValidBucket(bucket string) bool {
@@ -85,6 +85,15 @@ type BucketNotFound GenericBucketError
 // TooManyBuckets - total buckets exceeded
 type TooManyBuckets GenericBucketError
 
+// BucketNotEmpty - bucket still holds objects or in-progress multipart uploads
+type BucketNotEmpty GenericBucketError
+
+// BucketResourceNotFound - requested resource is not set on the bucket
+type BucketResourceNotFound struct {
+	Bucket   string
+	Resource string
+}
+
 /// Object related errors
 
 // ObjectNotFound - requested object not found
@@ -93,6 +102,12 @@ type ObjectNotFound GenericObjectError
 // ObjectExists - object already exists
 type ObjectExists GenericObjectError
 
+// ObjectVersionNotFound - requested version of an object not found
+type ObjectVersionNotFound struct {
+	GenericObjectError
+	VersionID string
+}
+
 // EntityTooLarge - object size exceeds maximum limit
 type EntityTooLarge struct {
 	GenericObjectError
@@ -141,6 +156,11 @@ func (e ObjectNotFound) Error() string {
 	return "Object not Found: " + e.Bucket + "#" + e.Object
 }
 
+// Return string an error formatted as the given text
+func (e ObjectVersionNotFound) Error() string {
+	return "Object version not Found: " + e.Bucket + "#" + e.Object + "#" + e.VersionID
+}
+
 // Return string an error formatted as the given text
 func (e APINotImplemented) Error() string {
 	return "Api not implemented: " + e.API
@@ -171,6 +191,16 @@ func (e BucketNotFound) Error() string {
 	return "Bucket not Found: " + e.Bucket
 }
 
+// Return string an error formatted as the given text
+func (e BucketNotEmpty) Error() string {
+	return "Bucket not empty: " + e.Bucket
+}
+
+// Return string an error formatted as the given text
+func (e BucketResourceNotFound) Error() string {
+	return "Bucket resource not Found: " + e.Bucket + "?" + e.Resource
+}
+
 // Return string an error formatted as the given text
 func (e ObjectNameInvalid) Error() string {
 	return "Object name invalid: " + e.Bucket + "#" + e.Object
@@ -18,6 +18,7 @@ package filesystem
 
 import (
 	"os"
+	"path/filepath"
 	"sync"
 
 	"github.com/minio/minio/pkg/storage/drivers"
@@ -39,6 +40,8 @@ func Start(root string) (chan<- string, <-chan error, drivers.Driver) {
 	// internal related to multiparts
 	fs.multiparts = new(Multiparts)
 	fs.multiparts.ActiveSession = make(map[string]*MultipartSession)
+	// data of writes interrupted by a restart is never renamed into place
+	os.RemoveAll(filepath.Join(root, tmpDir))
 	go start(ctrlChannel, errorChannel, fs)
 	return ctrlChannel, errorChannel, fs
 }
@@ -19,6 +19,7 @@ package filesystem
 import (
 	"os"
 	"sort"
+	"strings"
 
 	"io/ioutil"
 	"path/filepath"
@@ -203,3 +204,125 @@ func (fs *fsDriver) ListObjects(bucket string, resources drivers.BucketResources
 	sort.Sort(byObjectKey(metadataList))
 	return metadataList, resources, nil
 }
+
+// DeleteBucket - DELETE Bucket, only empty buckets are removed
+func (fs *fsDriver) DeleteBucket(bucket string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	// verify bucket path legal
+	if drivers.IsValidBucket(bucket) == false {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+
+	// get bucket path
+	bucketDir := filepath.Join(fs.root, bucket)
+
+	// check if bucket exists
+	if _, err := os.Stat(bucketDir); os.IsNotExist(err) {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+
+	// objects, their metadata and any in-progress multipart parts are all regular files
+	// inside the bucket directory, directories alone do not make a bucket non empty
+	errBucketNotEmpty := drivers.BucketNotEmpty{Bucket: bucket}
+	err := filepath.Walk(bucketDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			return errBucketNotEmpty
+		}
+		return nil
+	})
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+
+	if err := os.RemoveAll(bucketDir); err != nil {
+		return iodine.New(err, nil)
+	}
+	// a bucket created again with the same name starts out without uploads
+	for key, session := range fs.multiparts.ActiveSession {
+		if session.Bucket == bucket {
+			delete(fs.multiparts.ActiveSession, key)
+		}
+	}
+	// remove the multipart session and bucket resources kept next to the bucket directory
+	files, err := ioutil.ReadDir(fs.root)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasPrefix(file.Name(), bucket+"$") {
+			continue
+		}
+		if err := os.Remove(filepath.Join(fs.root, file.Name())); err != nil && !os.IsNotExist(err) {
+			return iodine.New(err, nil)
+		}
+	}
+	return nil
+}
+
+// GetBucketResource - read the bucket resource kept in "<bucket>$<resource>" next to the bucket directory
+func (fs *fsDriver) GetBucketResource(bucket, resource string) ([]byte, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return nil, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	bucketDir := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketDir); os.IsNotExist(err) {
+		return nil, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	data, err := ioutil.ReadFile(bucketDir + "$" + resource)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, iodine.New(drivers.BucketResourceNotFound{Bucket: bucket, Resource: resource}, nil)
+		}
+		return nil, iodine.New(err, nil)
+	}
+	return data, nil
+}
+
+// SetBucketResource - write the bucket resource to "<bucket>$<resource>", replacing any previous one
+func (fs *fsDriver) SetBucketResource(bucket, resource string, data []byte) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	bucketDir := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketDir); os.IsNotExist(err) {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	// write aside and rename, readers never see a partially written resource
+	resourcePath := bucketDir + "$" + resource
+	if err := ioutil.WriteFile(resourcePath+"$tmp", data, 0600); err != nil {
+		return iodine.New(err, nil)
+	}
+	if err := os.Rename(resourcePath+"$tmp", resourcePath); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// DeleteBucketResource - remove "<bucket>$<resource>"
+func (fs *fsDriver) DeleteBucketResource(bucket, resource string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	bucketDir := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketDir); os.IsNotExist(err) {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	if err := os.Remove(bucketDir + "$" + resource); err != nil {
+		if os.IsNotExist(err) {
+			return iodine.New(drivers.BucketResourceNotFound{Bucket: bucket, Resource: resource}, nil)
+		}
+		return iodine.New(err, nil)
+	}
+	return nil
+}
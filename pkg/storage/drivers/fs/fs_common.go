@@ -19,17 +19,46 @@ package filesystem
 import (
 	"bufio"
 	"bytes"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/minio/minio/pkg/iodine"
 	"github.com/minio/minio/pkg/storage/drivers"
 )
 
+// tmpDir - directory under the root for data still being written, it is not a valid bucket name
+// so that an interrupted write never shows up in any bucket
+const tmpDir = "$tmp"
+
+// createTempFile - new file for data which is renamed into place once it is complete and verified
+func (fs *fsDriver) createTempFile() (*os.File, error) {
+	tmpPath := filepath.Join(fs.root, tmpDir)
+	if err := os.MkdirAll(tmpPath, 0700); err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	file, err := ioutil.TempFile(tmpPath, "data")
+	if err != nil {
+		return nil, iodine.New(err, nil)
+	}
+	return file, nil
+}
+
 // Metadata - carries metadata about object
 type Metadata struct {
 	Md5sum      []byte
 	ContentType string
+	Metadata    map[string]string
+	Tags        map[string]string
+	ACL         []drivers.Grant
+
+	// creation time and version of the object, the version id is empty for the null version
+	Created      time.Time
+	VersionID    string
+	DeleteMarker bool
 }
 
 func appendUniq(slice []string, i string) []string {
@@ -44,12 +73,25 @@ func appendUniq(slice []string, i string) []string {
 type bucketDir struct {
 	files map[string]os.FileInfo
 	root  string
+
+	// keys with earlier versions, collected only when not nil
+	versions map[string]bool
 }
 
 func (p *bucketDir) getAllFiles(object string, fl os.FileInfo, err error) error {
 	if err != nil {
 		return err
 	}
+	// earlier versions of an object are never listed as objects themselves
+	if fl.IsDir() && strings.HasSuffix(object, versionsSuffix) {
+		if p.versions != nil {
+			_p := strings.Split(strings.TrimSuffix(object, versionsSuffix), p.root+"/")
+			if len(_p) > 1 {
+				p.versions[_p[1]] = true
+			}
+		}
+		return filepath.SkipDir
+	}
 	if fl.Mode().IsRegular() {
 		if strings.HasSuffix(object, "$metadata") {
 			return nil
@@ -57,6 +99,9 @@ func (p *bucketDir) getAllFiles(object string, fl os.FileInfo, err error) error
 		if strings.HasSuffix(object, "$multiparts") {
 			return nil
 		}
+		if strings.HasSuffix(object, "$copy") {
+			return nil
+		}
 		matched, err := regexp.MatchString("\\$[0-9].*$", object)
 		if err != nil {
 			return nil
@@ -45,6 +45,15 @@ type MultipartSession struct {
 	UploadID   string
 	Initiated  time.Time
 	Parts      []*drivers.PartMetadata
+
+	// bucket of the upload, sessions are kept by object key alone
+	Bucket string
+
+	// content type, metadata, tags and grants of the completed object
+	ContentType string
+	Metadata    map[string]string
+	Tags        map[string]string
+	ACL         []drivers.Grant
 }
 
 // Multiparts collection of many parts
@@ -86,29 +95,43 @@ func (fs *fsDriver) isValidUploadID(key, uploadID string) bool {
 	return false
 }
 
-func (fs *fsDriver) writePart(objectPath string, partID int, size int64, data io.Reader) (drivers.PartMetadata, error) {
+// writePart - write a part aside first, it replaces an earlier upload of the same part only once it
+// is complete and matches the expected md5sum
+func (fs *fsDriver) writePart(bucket, key, objectPath string, partID int, expectedMD5Sum string, size int64, data io.Reader) (drivers.PartMetadata, error) {
 	partPath := objectPath + fmt.Sprintf("$%d", partID)
 	// write part
-	partFile, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE, 0600)
+	partFile, err := fs.createTempFile()
 	if err != nil {
 		return drivers.PartMetadata{}, iodine.New(err, nil)
 	}
-	defer partFile.Close()
+	tmpPath := partFile.Name()
+	defer os.Remove(tmpPath)
 
 	h := md5.New()
 	mw := io.MultiWriter(partFile, h)
 
 	_, err = io.CopyN(mw, data, size)
+	partFile.Close()
 	if err != nil {
 		return drivers.PartMetadata{}, iodine.New(err, nil)
 	}
 
+	partMetadata := drivers.PartMetadata{}
+	partMetadata.ETag = hex.EncodeToString(h.Sum(nil))
+	// Verify if the written part is equal to what is expected, only if it is requested as such
+	if strings.TrimSpace(expectedMD5Sum) != "" {
+		if err := isMD5SumEqual(strings.TrimSpace(expectedMD5Sum), partMetadata.ETag); err != nil {
+			return drivers.PartMetadata{}, iodine.New(drivers.BadDigest{Md5: expectedMD5Sum, Bucket: bucket, Key: key}, nil)
+		}
+	}
+	if err := os.Rename(tmpPath, partPath); err != nil {
+		return drivers.PartMetadata{}, iodine.New(err, nil)
+	}
+
 	fi, err := os.Stat(partPath)
 	if err != nil {
 		return drivers.PartMetadata{}, iodine.New(err, nil)
 	}
-	partMetadata := drivers.PartMetadata{}
-	partMetadata.ETag = hex.EncodeToString(h.Sum(nil))
 	partMetadata.PartNumber = partID
 	partMetadata.Size = fi.Size()
 	partMetadata.LastModified = fi.ModTime()
@@ -213,7 +236,7 @@ func (fs *fsDriver) concatParts(parts map[int]string, objectPath string, mw io.W
 	return nil
 }
 
-func (fs *fsDriver) NewMultipartUpload(bucket, key, contentType string) (string, error) {
+func (fs *fsDriver) NewMultipartUpload(bucket, key, contentType string, attributes drivers.ObjectAttributes) (string, error) {
 	fs.lock.Lock()
 	defer fs.lock.Unlock()
 	if !drivers.IsValidBucket(bucket) {
@@ -241,8 +264,12 @@ func (fs *fsDriver) NewMultipartUpload(bucket, key, contentType string) (string,
 		}
 	}
 
-	// check if object exists
-	if _, err := os.Stat(objectPath); !os.IsNotExist(err) {
+	// check if object exists, with versioning it becomes an earlier version on completion
+	status, err := getBucketVersioning(bucketPath)
+	if err != nil {
+		return "", iodine.New(err, nil)
+	}
+	if _, err := os.Stat(objectPath); status == "" && !os.IsNotExist(err) {
 		return "", iodine.New(drivers.ObjectExists{
 			Bucket: bucket,
 			Object: key,
@@ -279,6 +306,11 @@ func (fs *fsDriver) NewMultipartUpload(bucket, key, contentType string) (string,
 	mpartSession.TotalParts = 0
 	mpartSession.UploadID = uploadID
 	mpartSession.Initiated = time.Now().UTC()
+	mpartSession.Bucket = bucket
+	mpartSession.ContentType = contentType
+	mpartSession.Metadata = attributes.Metadata
+	mpartSession.Tags = attributes.Tags
+	mpartSession.ACL = attributes.ACL
 	var parts []*drivers.PartMetadata
 	mpartSession.Parts = parts
 	fs.multiparts.ActiveSession[key] = mpartSession
@@ -354,25 +386,22 @@ func (fs *fsDriver) CreateObjectPart(bucket, key, uploadID string, partID int, c
 		}
 	}
 
-	// check if object exists
-	if _, err := os.Stat(objectPath); !os.IsNotExist(err) {
+	// check if object exists, with versioning it becomes an earlier version on completion
+	status, err := getBucketVersioning(bucketPath)
+	if err != nil {
+		return "", iodine.New(err, nil)
+	}
+	if _, err := os.Stat(objectPath); status == "" && !os.IsNotExist(err) {
 		return "", iodine.New(drivers.ObjectExists{
 			Bucket: bucket,
 			Object: key,
 		}, nil)
 	}
-	partMetadata, err := fs.writePart(objectPath, partID, size, data)
+	partMetadata, err := fs.writePart(bucket, key, objectPath, partID, expectedMD5Sum, size, data)
 	if err != nil {
 		return "", iodine.New(err, nil)
 	}
 
-	// Verify if the written object is equal to what is expected, only if it is requested as such
-	if strings.TrimSpace(expectedMD5Sum) != "" {
-		if err := isMD5SumEqual(strings.TrimSpace(expectedMD5Sum), partMetadata.ETag); err != nil {
-			return "", iodine.New(drivers.BadDigest{Md5: expectedMD5Sum, Bucket: bucket, Key: key}, nil)
-		}
-	}
-
 	multiPartfile, err := os.OpenFile(objectPath+"$multiparts", os.O_RDWR|os.O_APPEND, 0600)
 	if err != nil {
 		return "", iodine.New(err, nil)
@@ -398,96 +427,113 @@ func (fs *fsDriver) CreateObjectPart(bucket, key, uploadID string, partID int, c
 	return partMetadata.ETag, nil
 }
 
-func (fs *fsDriver) CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (string, error) {
+func (fs *fsDriver) CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (drivers.ObjectMetadata, error) {
 	fs.lock.Lock()
 	defer fs.lock.Unlock()
 
 	// check bucket name valid
 	if drivers.IsValidBucket(bucket) == false {
-		return "", iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
 	}
 
 	// verify object path legal
 	if drivers.IsValidObjectName(key) == false {
-		return "", iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: key}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: key}, nil)
 	}
 
 	if !fs.isValidUploadID(key, uploadID) {
-		return "", iodine.New(drivers.InvalidUploadID{UploadID: uploadID}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.InvalidUploadID{UploadID: uploadID}, nil)
 	}
 
 	bucketPath := filepath.Join(fs.root, bucket)
 	_, err := os.Stat(bucketPath)
 	// check bucket exists
 	if os.IsNotExist(err) {
-		return "", iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
 	}
 	if err != nil {
-		return "", iodine.New(drivers.InternalError{}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.InternalError{}, nil)
 	}
 
+	// parts are concatenated aside, the current object is only replaced once they are all in place
 	objectPath := filepath.Join(bucketPath, key)
-	// check if object exists
-	if _, err := os.Stat(objectPath); !os.IsNotExist(err) {
-		return "", iodine.New(drivers.ObjectExists{
-			Bucket: bucket,
-			Object: key,
-		}, nil)
-	}
-
-	file, err := os.OpenFile(objectPath, os.O_WRONLY|os.O_CREATE, 0600)
+	file, err := fs.createTempFile()
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
-	defer file.Close()
+	tmpPath := file.Name()
+	defer os.Remove(tmpPath)
 	h := md5.New()
 	mw := io.MultiWriter(file, h)
 	err = fs.concatParts(parts, objectPath, mw)
+	file.Close()
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
-	md5sum := hex.EncodeToString(h.Sum(nil))
 
+	// check if object exists, with versioning it becomes an earlier version instead
+	versionID, err := replaceObject(bucketPath, objectPath, bucket, key)
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	if err := os.Rename(tmpPath, objectPath); err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+
+	session := fs.multiparts.ActiveSession[key]
 	delete(fs.multiparts.ActiveSession, key)
 	for partNumber := range parts {
 		err = os.Remove(objectPath + fmt.Sprintf("$%d", partNumber))
 		if err != nil {
-			return "", iodine.New(err, nil)
+			return drivers.ObjectMetadata{}, iodine.New(err, nil)
 		}
 	}
 	err = os.Remove(objectPath + "$multiparts")
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
 
-	file, err = os.OpenFile(objectPath+"$metadata", os.O_WRONLY|os.O_CREATE, 0600)
+	file, err = os.OpenFile(objectPath+"$metadata", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
 	defer file.Close()
 
+	contentType := "application/octet-stream"
+	if strings.TrimSpace(session.ContentType) != "" {
+		contentType = strings.TrimSpace(session.ContentType)
+	}
 	metadata := &Metadata{
-		ContentType: "application/octet-stream",
+		ContentType: contentType,
+		Metadata:    session.Metadata,
+		Tags:        session.Tags,
+		ACL:         session.ACL,
 		Md5sum:      h.Sum(nil),
+		Created:     time.Now().UTC(),
+		VersionID:   versionID,
 	}
 	// serialize metadata to json
 	encoder := json.NewEncoder(file)
 	err = encoder.Encode(metadata)
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
 
 	activeSessionFile, err := os.OpenFile(bucketPath+"$activeSession", os.O_WRONLY|os.O_TRUNC, 0600)
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
 	defer activeSessionFile.Close()
 	encoder = json.NewEncoder(activeSessionFile)
 	err = encoder.Encode(fs.multiparts.ActiveSession)
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	completedMetadata, err := fs.GetObjectMetadata(bucket, key)
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
-	return md5sum, nil
+	return completedMetadata, nil
 }
 
 func (fs *fsDriver) ListObjectParts(bucket, key string, resources drivers.ObjectResourcesMetadata) (drivers.ObjectResourcesMetadata, error) {
@@ -614,5 +660,17 @@ func (fs *fsDriver) AbortMultipartUpload(bucket, key, uploadID string) error {
 	if err != nil {
 		return iodine.New(err, nil)
 	}
+
+	// aborted session must not be loaded again
+	activeSessionFile, err := os.OpenFile(bucketPath+"$activeSession", os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	defer activeSessionFile.Close()
+	encoder := json.NewEncoder(activeSessionFile)
+	err = encoder.Encode(fs.multiparts.ActiveSession)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
 	return nil
 }
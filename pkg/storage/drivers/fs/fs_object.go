@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"crypto/md5"
 	"encoding/base64"
@@ -178,11 +179,74 @@ func (fs *fsDriver) GetObjectMetadata(bucket, object string) (drivers.ObjectMeta
 		Size:        stat.Size(),
 		Md5:         etag,
 		ContentType: contentType,
+		Metadata:    deserializedMetadata.Metadata,
+		Tags:        deserializedMetadata.Tags,
+		ACL:         deserializedMetadata.ACL,
+		VersionID:   deserializedMetadata.VersionID,
+		IsLatest:    true,
 	}
 
 	return metadata, nil
 }
 
+// SetObjectTags - replace the tags kept in the metadata of an object
+func (fs *fsDriver) SetObjectTags(bucket, key string, tags map[string]string) error {
+	return fs.updateObjectMetadata(bucket, key, func(metadata *Metadata) {
+		metadata.Tags = tags
+	})
+}
+
+// SetObjectACL - replace the ACL grants kept in the metadata of an object
+func (fs *fsDriver) SetObjectACL(bucket, key string, grants []drivers.Grant) error {
+	return fs.updateObjectMetadata(bucket, key, func(metadata *Metadata) {
+		metadata.ACL = grants
+	})
+}
+
+// updateObjectMetadata - rewrite the metadata of an object after applying update to it
+func (fs *fsDriver) updateObjectMetadata(bucket, key string, update func(*Metadata)) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	if drivers.IsValidBucket(bucket) == false {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if drivers.IsValidObjectName(key) == false {
+		return iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: key}, nil)
+	}
+	if _, err := os.Stat(filepath.Join(fs.root, bucket)); os.IsNotExist(err) {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+
+	objectPath := fs.root + "/" + bucket + "/" + key
+	file, err := os.Open(objectPath + "$metadata")
+	if os.IsNotExist(err) {
+		return iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+	}
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	var metadata Metadata
+	err = json.NewDecoder(file).Decode(&metadata)
+	file.Close()
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	update(&metadata)
+
+	file, err = os.OpenFile(objectPath+"$metadata", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	defer file.Close()
+	// serialize metadata to json
+	encoder := json.NewEncoder(file)
+	if err := encoder.Encode(&metadata); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
 // isMD5SumEqual - returns error if md5sum mismatches, success its `nil`
 func isMD5SumEqual(expectedMD5Sum, actualMD5Sum string) error {
 	if strings.TrimSpace(expectedMD5Sum) != "" && strings.TrimSpace(actualMD5Sum) != "" {
@@ -202,24 +266,22 @@ func isMD5SumEqual(expectedMD5Sum, actualMD5Sum string) error {
 	return iodine.New(errors.New("invalid argument"), nil)
 }
 
-// CreateObject - PUT object
-func (fs *fsDriver) CreateObject(bucket, key, contentType, expectedMD5Sum string, size int64, data io.Reader) (string, error) {
-	fs.lock.Lock()
-	defer fs.lock.Unlock()
-
+// CreateObject - PUT object, the data is written aside and only replaces the current object once it is complete
+func (fs *fsDriver) CreateObject(bucket, key, contentType, expectedMD5Sum string, size int64, data io.Reader, attributes drivers.ObjectAttributes) (drivers.ObjectMetadata, error) {
 	// check bucket name valid
 	if drivers.IsValidBucket(bucket) == false {
-		return "", iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
-	}
-
-	// check bucket exists
-	if _, err := os.Stat(filepath.Join(fs.root, bucket)); os.IsNotExist(err) {
-		return "", iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
 	}
 
 	// verify object path legal
 	if drivers.IsValidObjectName(key) == false {
-		return "", iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: key}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: key}, nil)
+	}
+
+	// check bucket exists
+	bucketPath := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketPath); os.IsNotExist(err) {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
 	}
 
 	// verify content type
@@ -228,68 +290,342 @@ func (fs *fsDriver) CreateObject(bucket, key, contentType, expectedMD5Sum string
 	}
 	contentType = strings.TrimSpace(contentType)
 
-	// get object path
-	objectPath := filepath.Join(fs.root, bucket, key)
-	objectDir := filepath.Dir(objectPath)
-	if _, err := os.Stat(objectDir); os.IsNotExist(err) {
-		err = os.MkdirAll(objectDir, 0700)
-		if err != nil {
-			return "", iodine.New(err, nil)
-		}
-	}
-
-	// check if object exists
-	if _, err := os.Stat(objectPath); !os.IsNotExist(err) {
-		return "", iodine.New(drivers.ObjectExists{
-			Bucket: bucket,
-			Object: key,
-		}, nil)
-	}
-
 	if strings.TrimSpace(expectedMD5Sum) != "" {
 		expectedMD5SumBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(expectedMD5Sum))
 		if err != nil {
 			// pro-actively close the connection
-			return "", iodine.New(drivers.InvalidDigest{Md5: expectedMD5Sum}, nil)
+			return drivers.ObjectMetadata{}, iodine.New(drivers.InvalidDigest{Md5: expectedMD5Sum}, nil)
 		}
 		expectedMD5Sum = hex.EncodeToString(expectedMD5SumBytes)
 	}
 
 	// write object
-	file, err := os.OpenFile(objectPath, os.O_WRONLY|os.O_CREATE, 0600)
+	file, err := fs.createTempFile()
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
-	defer file.Close()
+	tmpPath := file.Name()
+	defer os.Remove(tmpPath)
 
 	h := md5.New()
 	mw := io.MultiWriter(file, h)
 
 	_, err = io.CopyN(mw, data, size)
+	file.Close()
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+
+	md5Sum := hex.EncodeToString(h.Sum(nil))
+	// Verify if the written object is equal to what is expected, only if it is requested as such
+	if strings.TrimSpace(expectedMD5Sum) != "" {
+		if err := isMD5SumEqual(strings.TrimSpace(expectedMD5Sum), md5Sum); err != nil {
+			return drivers.ObjectMetadata{}, iodine.New(drivers.BadDigest{Md5: expectedMD5Sum, Bucket: bucket, Key: key}, nil)
+		}
+	}
+
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	// the bucket may be gone by now
+	if _, err := os.Stat(bucketPath); os.IsNotExist(err) {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+
+	// get object path
+	objectPath := filepath.Join(bucketPath, key)
+	objectDir := filepath.Dir(objectPath)
+	if _, err := os.Stat(objectDir); os.IsNotExist(err) {
+		err = os.MkdirAll(objectDir, 0700)
+		if err != nil {
+			return drivers.ObjectMetadata{}, iodine.New(err, nil)
+		}
+	}
+
+	// check if object exists, with versioning it becomes an earlier version instead
+	versionID, err := replaceObject(bucketPath, objectPath, bucket, key)
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	if err := os.Rename(tmpPath, objectPath); err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
 
-	file, err = os.OpenFile(objectPath+"$metadata", os.O_WRONLY|os.O_CREATE, 0600)
+	file, err = os.OpenFile(objectPath+"$metadata", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
 	defer file.Close()
 
 	metadata := &Metadata{
 		ContentType: contentType,
 		Md5sum:      h.Sum(nil),
+		Metadata:    attributes.Metadata,
+		Tags:        attributes.Tags,
+		ACL:         attributes.ACL,
+		Created:     time.Now().UTC(),
+		VersionID:   versionID,
 	}
 	// serialize metadata to json
 	encoder := json.NewEncoder(file)
-	err = encoder.Encode(metadata)
+	if err := encoder.Encode(metadata); err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	createdMetadata, err := fs.GetObjectMetadata(bucket, key)
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	return createdMetadata, nil
+}
 
-	md5Sum := hex.EncodeToString(metadata.Md5sum)
-	// Verify if the written object is equal to what is expected, only if it is requested as such
-	if strings.TrimSpace(expectedMD5Sum) != "" {
-		if err := isMD5SumEqual(strings.TrimSpace(expectedMD5Sum), md5Sum); err != nil {
-			return "", iodine.New(drivers.BadDigest{Md5: expectedMD5Sum, Bucket: bucket, Key: key}, nil)
+// CopyObject - copy an object locally, an empty contentType keeps the content type and metadata of the source
+func (fs *fsDriver) CopyObject(sourceBucket, sourceKey, bucket, key, contentType string, objectMetadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	return fs.copyObject(sourceBucket, sourceKey, "", bucket, key, contentType, objectMetadata, grants)
+}
+
+// CopyObjectVersion - copy a version of an object locally, an empty contentType keeps the content type and metadata of the source
+func (fs *fsDriver) CopyObjectVersion(sourceBucket, sourceKey, sourceVersionID, bucket, key, contentType string, objectMetadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	return fs.copyObject(sourceBucket, sourceKey, sourceVersionID, bucket, key, contentType, objectMetadata, grants)
+}
+
+// copyObject - copy a version of an object, an empty sourceVersionID copies the current object. Caller holds the lock
+func (fs *fsDriver) copyObject(sourceBucket, sourceKey, sourceVersionID, bucket, key, contentType string, objectMetadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+
+	// check bucket names valid
+	if drivers.IsValidBucket(sourceBucket) == false {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: sourceBucket}, nil)
+	}
+	if drivers.IsValidBucket(bucket) == false {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+
+	// verify object paths legal
+	if drivers.IsValidObjectName(sourceKey) == false {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Bucket: sourceBucket, Object: sourceKey}, nil)
+	}
+	if drivers.IsValidObjectName(key) == false {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: key}, nil)
+	}
+
+	// check buckets exist
+	if _, err := os.Stat(filepath.Join(fs.root, sourceBucket)); os.IsNotExist(err) {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNotFound{Bucket: sourceBucket}, nil)
+	}
+	if _, err := os.Stat(filepath.Join(fs.root, bucket)); os.IsNotExist(err) {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+
+	sourcePath, metadata, err := fs.getCopySource(sourceBucket, sourceKey, sourceVersionID)
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	if strings.TrimSpace(contentType) != "" {
+		metadata.ContentType = strings.TrimSpace(contentType)
+		metadata.Metadata = objectMetadata
+	}
+	// the copy gets the grants it is created with rather than those of the source
+	metadata.ACL = grants
+
+	// get object path
+	objectPath := filepath.Join(fs.root, bucket, key)
+	objectDir := filepath.Dir(objectPath)
+	if _, err := os.Stat(objectDir); os.IsNotExist(err) {
+		err = os.MkdirAll(objectDir, 0700)
+		if err != nil {
+			return drivers.ObjectMetadata{}, iodine.New(err, nil)
+		}
+	}
+
+	// data is copied rather than hard linked, a link would share the modification time of
+	// the source which is what reports the last modified time of an object. With versioning
+	// the source may be the very object replaced, so the data is copied aside first
+	file, err := fs.createTempFile()
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	copyPath := file.Name()
+	defer os.Remove(copyPath)
+	err = copyData(sourcePath, file)
+	file.Close()
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+
+	// check if object exists, with versioning it becomes an earlier version instead.
+	// An object copied onto itself is replaced by its copy
+	versionID, err := replaceObject(filepath.Join(fs.root, bucket), objectPath, bucket, key)
+	switch iodine.ToError(err).(type) {
+	case nil:
+	case drivers.ObjectExists:
+		if sourceBucket != bucket || sourceKey != key {
+			return drivers.ObjectMetadata{}, iodine.New(err, nil)
+		}
+	default:
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	if err := os.Rename(copyPath, objectPath); err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	metadata.Created = time.Now().UTC()
+	metadata.VersionID = versionID
+
+	file, err = os.OpenFile(objectPath+"$metadata", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	defer file.Close()
+	// serialize metadata to json
+	encoder := json.NewEncoder(file)
+	if err := encoder.Encode(&metadata); err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	copyMetadata, err := fs.GetObjectMetadata(bucket, key)
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	return copyMetadata, nil
+}
+
+// getCopySource - path and metadata of the version of an object a copy is made from, an empty versionID
+// finds the current object
+func (fs *fsDriver) getCopySource(bucket, key, versionID string) (string, Metadata, error) {
+	if versionID != "" {
+		version, _, err := fs.getObjectVersion(bucket, key, versionID)
+		if err != nil {
+			return "", Metadata{}, iodine.New(err, nil)
+		}
+		// delete markers have no data to copy
+		if version.metadata.DeleteMarker {
+			return "", Metadata{}, iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+		}
+		return version.path, version.metadata, nil
+	}
+	sourcePath := filepath.Join(fs.root, bucket, key)
+	filestat, err := os.Stat(sourcePath)
+	if os.IsNotExist(err) || (err == nil && filestat.IsDir()) {
+		return "", Metadata{}, iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+	}
+	if err != nil {
+		return "", Metadata{}, iodine.New(err, nil)
+	}
+	file, err := os.Open(sourcePath + "$metadata")
+	if os.IsNotExist(err) {
+		return "", Metadata{}, iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+	}
+	if err != nil {
+		return "", Metadata{}, iodine.New(err, nil)
+	}
+	defer file.Close()
+	var metadata Metadata
+	if err := json.NewDecoder(file).Decode(&metadata); err != nil {
+		return "", Metadata{}, iodine.New(err, nil)
+	}
+	return sourcePath, metadata, nil
+}
+
+// copyData - copy the contents of the file at source to target
+func copyData(source string, target io.Writer) error {
+	sourceFile, err := os.Open(source)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	defer sourceFile.Close()
+	if _, err := io.Copy(target, sourceFile); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// DeleteObject - DELETE object along with its metadata
+func (fs *fsDriver) DeleteObject(bucket, key string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	// check bucket name valid
+	if drivers.IsValidBucket(bucket) == false {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+
+	// check bucket exists
+	bucketPath := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketPath); os.IsNotExist(err) {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+
+	return fs.deleteObject(bucketPath, bucket, key)
+}
+
+// DeleteObjects - DELETE several objects of a bucket along with their metadata, errors are returned per key
+func (fs *fsDriver) DeleteObjects(bucket string, keys []string) (map[string]error, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	// check bucket name valid
+	if drivers.IsValidBucket(bucket) == false {
+		return nil, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+
+	// check bucket exists
+	bucketPath := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketPath); os.IsNotExist(err) {
+		return nil, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+
+	errs := make(map[string]error)
+	for _, key := range keys {
+		if err := fs.deleteObject(bucketPath, bucket, key); err != nil {
+			errs[key] = err
 		}
 	}
-	return md5Sum, nil
+	return errs, nil
+}
+
+// deleteObject - remove object and its metadata from an existing bucket, caller holds the lock
+func (fs *fsDriver) deleteObject(bucketPath, bucket, key string) error {
+	// verify object path legal
+	if drivers.IsValidObjectName(key) == false {
+		return iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: key}, nil)
+	}
+
+	// with versioning the object is replaced by a delete marker, keeping its earlier versions
+	status, err := getBucketVersioning(bucketPath)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	if status != "" {
+		return createDeleteMarker(bucketPath, bucket, key, status)
+	}
+
+	objectPath := filepath.Join(bucketPath, key)
+	filestat, err := os.Stat(objectPath)
+	switch err := err.(type) {
+	case nil:
+		{
+			if filestat.IsDir() {
+				return iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+			}
+		}
+	default:
+		{
+			if os.IsNotExist(err) {
+				return iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+			}
+			return iodine.New(err, nil)
+		}
+	}
+
+	if err := os.Remove(objectPath); err != nil {
+		return iodine.New(err, nil)
+	}
+	if err := os.Remove(objectPath + "$metadata"); err != nil && !os.IsNotExist(err) {
+		return iodine.New(err, nil)
+	}
+
+	// remove any parent directories left empty by this object
+	removeEmptyDirs(filepath.Dir(objectPath), bucketPath)
+	return nil
 }
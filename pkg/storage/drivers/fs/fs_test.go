@@ -19,6 +19,7 @@ package filesystem
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/minio/check"
@@ -45,6 +46,35 @@ func (s *MySuite) TestAPISuite(c *C) {
 	defer removeRoots(c, storageList)
 }
 
+func (s *MySuite) TestDeleteBucketUploads(c *C) {
+	root, err := ioutil.TempDir(os.TempDir(), "minio-fs-")
+	c.Assert(err, IsNil)
+	defer removeRoots(c, []string{root})
+	_, _, store := Start(root)
+
+	for _, bucket := range []string{"bucket", "otherbucket"} {
+		err = store.CreateBucket(bucket, "")
+		c.Assert(err, IsNil)
+	}
+	_, err = store.NewMultipartUpload("bucket", "key", "", drivers.ObjectAttributes{})
+	c.Assert(err, IsNil)
+	_, err = store.NewMultipartUpload("otherbucket", "otherkey", "", drivers.ObjectAttributes{})
+	c.Assert(err, IsNil)
+
+	// an upload whose parts are gone no longer keeps its bucket from being deleted
+	err = os.Remove(filepath.Join(root, "bucket", "key$multiparts"))
+	c.Assert(err, IsNil)
+	err = store.DeleteBucket("bucket")
+	c.Assert(err, IsNil)
+
+	// only the uploads of the deleted bucket are dropped
+	sessions := store.(*fsDriver).multiparts.ActiveSession
+	_, ok := sessions["key"]
+	c.Assert(ok, Equals, false)
+	_, ok = sessions["otherkey"]
+	c.Assert(ok, Equals, true)
+}
+
 func removeRoots(c *C, roots []string) {
 	for _, root := range roots {
 		err := os.RemoveAll(root)
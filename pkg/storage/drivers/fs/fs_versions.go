@@ -0,0 +1,525 @@
+/*
+ * Minimalist Object Storage, (C) 2015 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package filesystem
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio/pkg/iodine"
+	"github.com/minio/minio/pkg/storage/drivers"
+)
+
+/// Object Version Operations
+//
+// The current version of an object stays at its key, earlier versions and delete markers
+// are kept in "<key>$versions" named by their version id, with metadata next to each of them.
+// Whenever the current object exists it is the latest version of its key.
+
+// versionsSuffix - suffix of the directory holding the earlier versions of a key
+const versionsSuffix = "$versions"
+
+// objectVersion - a version of an object, data at path and metadata at path$metadata
+type objectVersion struct {
+	path     string
+	created  time.Time
+	size     int64
+	metadata Metadata
+}
+
+// byVersionCreated is a type for sorting versions newest first
+type byVersionCreated []objectVersion
+
+func (b byVersionCreated) Len() int           { return len(b) }
+func (b byVersionCreated) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byVersionCreated) Less(i, j int) bool { return b[i].created.After(b[j].created) }
+
+// newVersionID - generate a new version id
+func newVersionID(bucket, key string) string {
+	id := []byte(strconv.FormatInt(rand.Int63(), 10) + bucket + key + time.Now().String())
+	versionIDSum := sha512.Sum512(id)
+	return base64.URLEncoding.EncodeToString(versionIDSum[:])[:32]
+}
+
+// isValidVersionID - version ids name files, they never lead outside of the versions directory
+func isValidVersionID(versionID string) bool {
+	return versionID != "." && versionID != ".." && !strings.ContainsAny(versionID, "/\\")
+}
+
+// getBucketVersioning - versioning status kept in "<bucket>$versioning" next to the bucket directory
+func getBucketVersioning(bucketPath string) (string, error) {
+	data, err := ioutil.ReadFile(bucketPath + "$versioning")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", iodine.New(err, nil)
+	}
+	return string(data), nil
+}
+
+// GetBucketVersioning - versioning status of a bucket
+func (fs *fsDriver) GetBucketVersioning(bucket string) (string, error) {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return "", iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	bucketPath := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketPath); os.IsNotExist(err) {
+		return "", iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	status, err := getBucketVersioning(bucketPath)
+	if err != nil {
+		return "", iodine.New(err, nil)
+	}
+	return status, nil
+}
+
+// SetBucketVersioning - write the versioning status to "<bucket>$versioning"
+func (fs *fsDriver) SetBucketVersioning(bucket, status string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	bucketPath := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketPath); os.IsNotExist(err) {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	versioningPath := bucketPath + "$versioning"
+	if err := ioutil.WriteFile(versioningPath+"$tmp", []byte(status), 0600); err != nil {
+		return iodine.New(err, nil)
+	}
+	if err := os.Rename(versioningPath+"$tmp", versioningPath); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// readVersion - read the version of an object at path
+func readVersion(path string) (objectVersion, error) {
+	file, err := os.Open(path + "$metadata")
+	if err != nil {
+		return objectVersion{}, iodine.New(err, nil)
+	}
+	defer file.Close()
+	version := objectVersion{path: path}
+	if err := json.NewDecoder(file).Decode(&version.metadata); err != nil {
+		return objectVersion{}, iodine.New(err, nil)
+	}
+	// delete markers have no data
+	stat, err := file.Stat()
+	if !version.metadata.DeleteMarker {
+		stat, err = os.Stat(path)
+	}
+	if err != nil {
+		return objectVersion{}, iodine.New(err, nil)
+	}
+	if !version.metadata.DeleteMarker {
+		version.size = stat.Size()
+	}
+	// objects written before versioning carry no creation time in their metadata
+	version.created = version.metadata.Created
+	if version.created.IsZero() {
+		version.created = stat.ModTime()
+	}
+	return version, nil
+}
+
+// listVersions - every version of the object at objectPath, newest first
+func listVersions(objectPath string) ([]objectVersion, error) {
+	var versions []objectVersion
+	if stat, err := os.Stat(objectPath); err == nil && stat.Mode().IsRegular() {
+		version, err := readVersion(objectPath)
+		if err != nil {
+			return nil, iodine.New(err, nil)
+		}
+		versions = append(versions, version)
+	}
+	versionsPath := objectPath + versionsSuffix
+	files, err := ioutil.ReadDir(versionsPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, iodine.New(err, nil)
+	}
+	var earlierVersions []objectVersion
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), "$metadata") {
+			continue
+		}
+		version, err := readVersion(filepath.Join(versionsPath, strings.TrimSuffix(file.Name(), "$metadata")))
+		if err != nil {
+			return nil, iodine.New(err, nil)
+		}
+		earlierVersions = append(earlierVersions, version)
+	}
+	sort.Stable(byVersionCreated(earlierVersions))
+	return append(versions, earlierVersions...), nil
+}
+
+// findVersion - position of a version in a list of versions, an empty version id finds the latest one
+func findVersion(versions []objectVersion, versionID string) (int, bool) {
+	for i, version := range versions {
+		if versionID == "" || drivers.IsVersionMatch(version.metadata.VersionID, versionID) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// toObjectMetadata - object metadata of a version
+func (v objectVersion) toObjectMetadata(bucket, key string, isLatest bool) drivers.ObjectMetadata {
+	metadata := drivers.ObjectMetadata{
+		Bucket:         bucket,
+		Key:            key,
+		Created:        v.created,
+		Size:           v.size,
+		Metadata:       v.metadata.Metadata,
+		Tags:           v.metadata.Tags,
+		ACL:            v.metadata.ACL,
+		VersionID:      v.metadata.VersionID,
+		IsLatest:       isLatest,
+		IsDeleteMarker: v.metadata.DeleteMarker,
+	}
+	if v.metadata.DeleteMarker {
+		return metadata
+	}
+	metadata.ContentType = "application/octet-stream"
+	if v.metadata.ContentType != "" {
+		metadata.ContentType = strings.TrimSpace(v.metadata.ContentType)
+	}
+	metadata.Md5 = bucket + "#" + filepath.Base(key)
+	if len(v.metadata.Md5sum) != 0 {
+		metadata.Md5 = hex.EncodeToString(v.metadata.Md5sum)
+	}
+	return metadata
+}
+
+// moveVersion - move the data and metadata of a version
+func moveVersion(path, newPath string) error {
+	if err := os.Rename(path, newPath); err != nil && !os.IsNotExist(err) {
+		return iodine.New(err, nil)
+	}
+	if err := os.Rename(path+"$metadata", newPath+"$metadata"); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// removeVersion - remove the data and metadata of a version
+func removeVersion(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return iodine.New(err, nil)
+	}
+	if err := os.Remove(path + "$metadata"); err != nil && !os.IsNotExist(err) {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// archiveObject - move the current object at objectPath to the earlier versions of its key, with versioning
+// suspended there is only one null version which is replaced instead
+func archiveObject(objectPath, status string) error {
+	versionsPath := objectPath + versionsSuffix
+	if status == drivers.VersioningSuspended {
+		if err := removeVersion(filepath.Join(versionsPath, drivers.NullVersionID)); err != nil {
+			return iodine.New(err, nil)
+		}
+	}
+	if stat, err := os.Stat(objectPath); err != nil || !stat.Mode().IsRegular() {
+		return nil
+	}
+	version, err := readVersion(objectPath)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	if version.metadata.VersionID == "" && status == drivers.VersioningSuspended {
+		return removeVersion(objectPath)
+	}
+	versionID := version.metadata.VersionID
+	if versionID == "" {
+		versionID = drivers.NullVersionID
+	}
+	if err := os.MkdirAll(versionsPath, 0700); err != nil {
+		return iodine.New(err, nil)
+	}
+	return moveVersion(objectPath, filepath.Join(versionsPath, versionID))
+}
+
+// replaceObject - make way for a new object at objectPath and return its version id, without versioning
+// an existing object is never replaced
+func replaceObject(bucketPath, objectPath, bucket, key string) (string, error) {
+	status, err := getBucketVersioning(bucketPath)
+	if err != nil {
+		return "", iodine.New(err, nil)
+	}
+	if status == "" {
+		if _, err := os.Stat(objectPath); !os.IsNotExist(err) {
+			return "", iodine.New(drivers.ObjectExists{
+				Bucket: bucket,
+				Object: key,
+			}, nil)
+		}
+		return "", nil
+	}
+	if err := archiveObject(objectPath, status); err != nil {
+		return "", iodine.New(err, nil)
+	}
+	if status == drivers.VersioningEnabled {
+		return newVersionID(bucket, key), nil
+	}
+	return "", nil
+}
+
+// createDeleteMarker - replace the current object by a delete marker, its earlier versions are kept
+func createDeleteMarker(bucketPath, bucket, key, status string) error {
+	objectPath := filepath.Join(bucketPath, key)
+	if err := archiveObject(objectPath, status); err != nil {
+		return iodine.New(err, nil)
+	}
+	metadata := Metadata{
+		Created:      time.Now().UTC(),
+		DeleteMarker: true,
+	}
+	markerPath := filepath.Join(objectPath+versionsSuffix, drivers.NullVersionID)
+	if status == drivers.VersioningEnabled {
+		metadata.VersionID = newVersionID(bucket, key)
+		markerPath = filepath.Join(objectPath+versionsSuffix, metadata.VersionID)
+	}
+	if err := os.MkdirAll(filepath.Dir(markerPath), 0700); err != nil {
+		return iodine.New(err, nil)
+	}
+	file, err := os.OpenFile(markerPath+"$metadata", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	defer file.Close()
+	if err := json.NewEncoder(file).Encode(&metadata); err != nil {
+		return iodine.New(err, nil)
+	}
+	return nil
+}
+
+// restoreLatestVersion - after removing a version the latest remaining one becomes the current object,
+// unless it is a delete marker
+func restoreLatestVersion(objectPath string) error {
+	if _, err := os.Stat(objectPath); err == nil {
+		return nil
+	}
+	versions, err := listVersions(objectPath)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	if len(versions) == 0 || versions[0].metadata.DeleteMarker {
+		return nil
+	}
+	return moveVersion(versions[0].path, objectPath)
+}
+
+// removeEmptyDirs - remove directories left empty from dir up to the bucket directory, stop at the first one in use
+func removeEmptyDirs(dir, bucketPath string) {
+	for ; dir != bucketPath && strings.HasPrefix(dir, bucketPath); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+}
+
+// getObjectVersion - find a version of an object, tells as well whether it is the latest one
+func (fs *fsDriver) getObjectVersion(bucket, key, versionID string) (objectVersion, bool, error) {
+	if !drivers.IsValidBucket(bucket) {
+		return objectVersion{}, false, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if !drivers.IsValidObjectName(key) || strings.TrimSpace(key) == "" {
+		return objectVersion{}, false, iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: key}, nil)
+	}
+	bucketPath := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketPath); os.IsNotExist(err) {
+		return objectVersion{}, false, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	versionNotFound := drivers.ObjectVersionNotFound{
+		GenericObjectError: drivers.GenericObjectError{Bucket: bucket, Object: key},
+		VersionID:          versionID,
+	}
+	if !isValidVersionID(versionID) {
+		return objectVersion{}, false, iodine.New(versionNotFound, nil)
+	}
+	versions, err := listVersions(filepath.Join(bucketPath, key))
+	if err != nil {
+		return objectVersion{}, false, iodine.New(err, nil)
+	}
+	i, ok := findVersion(versions, versionID)
+	if !ok {
+		if versionID == "" {
+			return objectVersion{}, false, iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+		}
+		return objectVersion{}, false, iodine.New(versionNotFound, nil)
+	}
+	return versions[i], i == 0, nil
+}
+
+// GetObjectVersion - GET a version of an object
+func (fs *fsDriver) GetObjectVersion(w io.Writer, bucket, key, versionID string) (int64, error) {
+	version, _, err := fs.getObjectVersion(bucket, key, versionID)
+	if err != nil {
+		return 0, iodine.New(err, nil)
+	}
+	// delete markers have no data to read
+	if version.metadata.DeleteMarker {
+		return 0, iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+	}
+	file, err := os.Open(version.path)
+	if err != nil {
+		return 0, iodine.New(err, nil)
+	}
+	defer file.Close()
+	count, err := io.Copy(w, file)
+	if err != nil {
+		return count, iodine.New(err, nil)
+	}
+	return count, nil
+}
+
+// GetPartialObjectVersion - GET a range of a version of an object
+func (fs *fsDriver) GetPartialObjectVersion(w io.Writer, bucket, key, versionID string, start, length int64) (int64, error) {
+	version, _, err := fs.getObjectVersion(bucket, key, versionID)
+	if err != nil {
+		return 0, iodine.New(err, nil)
+	}
+	// delete markers have no data to read
+	if version.metadata.DeleteMarker {
+		return 0, iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+	}
+	file, err := os.Open(version.path)
+	if err != nil {
+		return 0, iodine.New(err, nil)
+	}
+	defer file.Close()
+	if _, err := file.Seek(start, os.SEEK_SET); err != nil {
+		return 0, iodine.New(err, nil)
+	}
+	count, err := io.CopyN(w, file, length)
+	if err != nil {
+		return count, iodine.New(err, nil)
+	}
+	return count, nil
+}
+
+// GetObjectVersionMetadata - HEAD a version of an object
+func (fs *fsDriver) GetObjectVersionMetadata(bucket, key, versionID string) (drivers.ObjectMetadata, error) {
+	version, isLatest, err := fs.getObjectVersion(bucket, key, versionID)
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	return version.toObjectMetadata(bucket, key, isLatest), nil
+}
+
+// DeleteObjectVersion - DELETE a version of an object for good, the latest remaining version becomes current
+func (fs *fsDriver) DeleteObjectVersion(bucket, key, versionID string) error {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if !drivers.IsValidObjectName(key) || strings.TrimSpace(key) == "" {
+		return iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: key}, nil)
+	}
+	bucketPath := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketPath); os.IsNotExist(err) {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	versionNotFound := drivers.ObjectVersionNotFound{
+		GenericObjectError: drivers.GenericObjectError{Bucket: bucket, Object: key},
+		VersionID:          versionID,
+	}
+	if !isValidVersionID(versionID) {
+		return iodine.New(versionNotFound, nil)
+	}
+	objectPath := filepath.Join(bucketPath, key)
+	versions, err := listVersions(objectPath)
+	if err != nil {
+		return iodine.New(err, nil)
+	}
+	i, ok := findVersion(versions, versionID)
+	if !ok {
+		return iodine.New(versionNotFound, nil)
+	}
+	if err := removeVersion(versions[i].path); err != nil {
+		return iodine.New(err, nil)
+	}
+	if err := restoreLatestVersion(objectPath); err != nil {
+		return iodine.New(err, nil)
+	}
+	removeEmptyDirs(objectPath+versionsSuffix, bucketPath)
+	removeEmptyDirs(filepath.Dir(objectPath), bucketPath)
+	return nil
+}
+
+// ListObjectVersions - GET bucket?versions, every version of the objects of a bucket
+func (fs *fsDriver) ListObjectVersions(bucket string, resources drivers.BucketResourcesMetadata) ([]drivers.ObjectMetadata, drivers.BucketResourcesMetadata, error) {
+	if !drivers.IsValidBucket(bucket) {
+		return nil, resources, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if resources.Prefix != "" && !drivers.IsValidObjectName(resources.Prefix) {
+		return nil, resources, iodine.New(drivers.ObjectNameInvalid{Bucket: bucket, Object: resources.Prefix}, nil)
+	}
+	bucketPath := filepath.Join(fs.root, bucket)
+	if _, err := os.Stat(bucketPath); os.IsNotExist(err) {
+		return nil, resources, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	p := bucketDir{
+		files:    make(map[string]os.FileInfo),
+		root:     bucketPath,
+		versions: make(map[string]bool),
+	}
+	if err := filepath.Walk(bucketPath, p.getAllFiles); err != nil {
+		return nil, resources, iodine.New(err, nil)
+	}
+	for key := range p.files {
+		p.versions[key] = true
+	}
+	var keys []string
+	for key := range p.versions {
+		if strings.HasPrefix(key, resources.Prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var versionList []drivers.ObjectMetadata
+	for _, key := range keys {
+		versions, err := listVersions(filepath.Join(bucketPath, key))
+		if err != nil {
+			return nil, resources, iodine.New(err, nil)
+		}
+		for i, version := range versions {
+			versionList = append(versionList, version.toObjectMetadata(bucket, key, i == 0))
+		}
+	}
+	versionList, resources = drivers.FilterObjectVersions(versionList, resources)
+	return versionList, resources, nil
+}
@@ -49,6 +49,7 @@ type memoryDriver struct {
 
 type storedBucket struct {
 	bucketMetadata   drivers.BucketMetadata
+	bucketResources  map[string][]byte
 	objectMetadata   map[string]drivers.ObjectMetadata
 	partMetadata     map[string]drivers.PartMetadata
 	multiPartSession map[string]multiPartSession
@@ -58,6 +59,9 @@ type multiPartSession struct {
 	totalParts int
 	uploadID   string
 	initiated  time.Time
+	// content type, metadata, tags and grants of the completed object
+	contentType string
+	attributes  drivers.ObjectAttributes
 }
 
 const (
@@ -188,6 +192,120 @@ func (memory *memoryDriver) SetBucketMetadata(bucket, acl string) error {
 	return nil
 }
 
+// DeleteBucket - delete bucket from memory, only if it has no objects or multipart uploads left
+func (memory *memoryDriver) DeleteBucket(bucket string) error {
+	memory.lock.Lock()
+	defer memory.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket]; ok == false {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	storedBucket := memory.storedBuckets[bucket]
+	if len(storedBucket.objectMetadata) > 0 || len(storedBucket.multiPartSession) > 0 {
+		return iodine.New(drivers.BucketNotEmpty{Bucket: bucket}, nil)
+	}
+	delete(memory.storedBuckets, bucket)
+	return nil
+}
+
+// GetBucketResource - get a copy of the bucket resource from memory
+func (memory *memoryDriver) GetBucketResource(bucket, resource string) ([]byte, error) {
+	memory.lock.RLock()
+	defer memory.lock.RUnlock()
+	if !drivers.IsValidBucket(bucket) {
+		return nil, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket]; ok == false {
+		return nil, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	data, ok := memory.storedBuckets[bucket].bucketResources[resource]
+	if !ok {
+		return nil, iodine.New(drivers.BucketResourceNotFound{Bucket: bucket, Resource: resource}, nil)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// SetBucketResource - set the bucket resource in memory, replacing any previous one
+func (memory *memoryDriver) SetBucketResource(bucket, resource string, data []byte) error {
+	memory.lock.Lock()
+	defer memory.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket]; ok == false {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	memory.storedBuckets[bucket].bucketResources[resource] = append([]byte(nil), data...)
+	return nil
+}
+
+// DeleteBucketResource - delete the bucket resource from memory
+func (memory *memoryDriver) DeleteBucketResource(bucket, resource string) error {
+	memory.lock.Lock()
+	defer memory.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket]; ok == false {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket].bucketResources[resource]; !ok {
+		return iodine.New(drivers.BucketResourceNotFound{Bucket: bucket, Resource: resource}, nil)
+	}
+	delete(memory.storedBuckets[bucket].bucketResources, resource)
+	return nil
+}
+
+// GetBucketVersioning - buckets in memory are never versioned
+func (memory *memoryDriver) GetBucketVersioning(bucket string) (string, error) {
+	memory.lock.RLock()
+	defer memory.lock.RUnlock()
+	if !drivers.IsValidBucket(bucket) {
+		return "", iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket]; ok == false {
+		return "", iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	return "", nil
+}
+
+// SetBucketVersioning - not implemented, objects in memory have a single version
+func (memory *memoryDriver) SetBucketVersioning(bucket, status string) error {
+	return iodine.New(drivers.APINotImplemented{API: "SetBucketVersioning"}, nil)
+}
+
+// GetObjectVersion - not implemented
+func (memory *memoryDriver) GetObjectVersion(w io.Writer, bucket, key, versionID string) (int64, error) {
+	return 0, iodine.New(drivers.APINotImplemented{API: "GetObjectVersion"}, nil)
+}
+
+// GetPartialObjectVersion - not implemented
+func (memory *memoryDriver) GetPartialObjectVersion(w io.Writer, bucket, key, versionID string, start, length int64) (int64, error) {
+	return 0, iodine.New(drivers.APINotImplemented{API: "GetPartialObjectVersion"}, nil)
+}
+
+// GetObjectVersionMetadata - not implemented
+func (memory *memoryDriver) GetObjectVersionMetadata(bucket, key, versionID string) (drivers.ObjectMetadata, error) {
+	return drivers.ObjectMetadata{}, iodine.New(drivers.APINotImplemented{API: "GetObjectVersionMetadata"}, nil)
+}
+
+// CopyObjectVersion - not implemented
+func (memory *memoryDriver) CopyObjectVersion(sourceBucket, sourceKey, sourceVersionID, bucket, key, contentType string, metadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	return drivers.ObjectMetadata{}, iodine.New(drivers.APINotImplemented{API: "CopyObjectVersion"}, nil)
+}
+
+// DeleteObjectVersion - not implemented
+func (memory *memoryDriver) DeleteObjectVersion(bucket, key, versionID string) error {
+	return iodine.New(drivers.APINotImplemented{API: "DeleteObjectVersion"}, nil)
+}
+
+// ListObjectVersions - not implemented
+func (memory *memoryDriver) ListObjectVersions(bucket string, resources drivers.BucketResourcesMetadata) ([]drivers.ObjectMetadata, drivers.BucketResourcesMetadata, error) {
+	return nil, drivers.BucketResourcesMetadata{}, iodine.New(drivers.APINotImplemented{API: "ListObjectVersions"}, nil)
+}
+
 // isMD5SumEqual - returns error if md5sum mismatches, success its `nil`
 func isMD5SumEqual(expectedMD5Sum, actualMD5Sum string) error {
 	if strings.TrimSpace(expectedMD5Sum) != "" && strings.TrimSpace(actualMD5Sum) != "" {
@@ -207,42 +325,115 @@ func isMD5SumEqual(expectedMD5Sum, actualMD5Sum string) error {
 	return iodine.New(errors.New("invalid argument"), nil)
 }
 
-func (memory *memoryDriver) CreateObject(bucket, key, contentType, expectedMD5Sum string, size int64, data io.Reader) (string, error) {
+func (memory *memoryDriver) CreateObject(bucket, key, contentType, expectedMD5Sum string, size int64, data io.Reader, attributes drivers.ObjectAttributes) (drivers.ObjectMetadata, error) {
 	if size > int64(memory.maxSize) {
 		generic := drivers.GenericObjectError{Bucket: bucket, Object: key}
-		return "", iodine.New(drivers.EntityTooLarge{
+		return drivers.ObjectMetadata{}, iodine.New(drivers.EntityTooLarge{
 			GenericObjectError: generic,
 			Size:               strconv.FormatInt(size, 10),
 			MaxSize:            strconv.FormatUint(memory.maxSize, 10),
 		}, nil)
 	}
-	md5sum, err := memory.createObject(bucket, key, contentType, expectedMD5Sum, size, data)
+	objectMetadata, err := memory.createObject(bucket, key, contentType, expectedMD5Sum, size, data, attributes)
 	// free
 	debug.FreeOSMemory()
-	return md5sum, iodine.New(err, nil)
+	return objectMetadata, iodine.New(err, nil)
+}
+
+// CopyObject - copy an object within memory, an empty contentType keeps the content type and metadata of the source
+func (memory *memoryDriver) CopyObject(sourceBucket, sourceKey, bucket, key, contentType string, metadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	memory.lock.RLock()
+	if !drivers.IsValidBucket(sourceBucket) {
+		memory.lock.RUnlock()
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: sourceBucket}, nil)
+	}
+	if !drivers.IsValidObjectName(sourceKey) {
+		memory.lock.RUnlock()
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Object: sourceKey}, nil)
+	}
+	if _, ok := memory.storedBuckets[sourceBucket]; ok == false {
+		memory.lock.RUnlock()
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNotFound{Bucket: sourceBucket}, nil)
+	}
+	sourceObjectKey := sourceBucket + "/" + sourceKey
+	sourceMetadata, ok := memory.storedBuckets[sourceBucket].objectMetadata[sourceObjectKey]
+	if !ok {
+		memory.lock.RUnlock()
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNotFound{Bucket: sourceBucket, Object: sourceKey}, nil)
+	}
+	data, ok := memory.objects.Get(sourceObjectKey)
+	if !ok {
+		memory.lock.RUnlock()
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNotFound{Bucket: sourceBucket, Object: sourceKey}, nil)
+	}
+	memory.lock.RUnlock()
+
+	if contentType == "" {
+		contentType = sourceMetadata.ContentType
+		metadata = sourceMetadata.Metadata
+	}
+	if sourceBucket == bucket && sourceKey == key {
+		return memory.replaceObjectMetadata(bucket, key, contentType, metadata, grants)
+	}
+	// tags are copied along with the data, the copy gets the grants it is created with
+	attributes := drivers.ObjectAttributes{
+		Metadata: metadata,
+		Tags:     sourceMetadata.Tags,
+		ACL:      grants,
+	}
+	objectMetadata, err := memory.createObject(bucket, key, contentType, "", int64(len(data)), bytes.NewReader(data), attributes)
+	if err != nil {
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
+	}
+	return objectMetadata, nil
+}
+
+// replaceObjectMetadata - an object copied onto itself keeps its data and tags, its metadata is replaced
+// and like any copy it gets the grants it is created with
+func (memory *memoryDriver) replaceObjectMetadata(bucket, key, contentType string, metadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	memory.lock.Lock()
+	defer memory.lock.Unlock()
+	objectKey := bucket + "/" + key
+	storedBucket := memory.storedBuckets[bucket]
+	objectMetadata, ok := storedBucket.objectMetadata[objectKey]
+	if !ok {
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	objectMetadata.ContentType = strings.TrimSpace(contentType)
+	objectMetadata.Created = time.Now().UTC()
+	objectMetadata.Metadata = make(map[string]string)
+	for name, value := range metadata {
+		objectMetadata.Metadata[name] = value
+	}
+	objectMetadata.ACL = grants
+	storedBucket.objectMetadata[objectKey] = objectMetadata
+	return objectMetadata, nil
 }
 
 // createObject - PUT object to memory buffer
-func (memory *memoryDriver) createObject(bucket, key, contentType, expectedMD5Sum string, size int64, data io.Reader) (string, error) {
+func (memory *memoryDriver) createObject(bucket, key, contentType, expectedMD5Sum string, size int64, data io.Reader, attributes drivers.ObjectAttributes) (drivers.ObjectMetadata, error) {
 	memory.lock.RLock()
 	if !drivers.IsValidBucket(bucket) {
 		memory.lock.RUnlock()
-		return "", iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
 	}
 	if !drivers.IsValidObjectName(key) {
 		memory.lock.RUnlock()
-		return "", iodine.New(drivers.ObjectNameInvalid{Object: key}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Object: key}, nil)
 	}
 	if _, ok := memory.storedBuckets[bucket]; ok == false {
 		memory.lock.RUnlock()
-		return "", iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
 	}
 	storedBucket := memory.storedBuckets[bucket]
 	// get object key
 	objectKey := bucket + "/" + key
 	if _, ok := storedBucket.objectMetadata[objectKey]; ok == true {
 		memory.lock.RUnlock()
-		return "", iodine.New(drivers.ObjectExists{Bucket: bucket, Object: key}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectExists{Bucket: bucket, Object: key}, nil)
 	}
 	memory.lock.RUnlock()
 
@@ -254,7 +445,7 @@ func (memory *memoryDriver) createObject(bucket, key, contentType, expectedMD5Su
 		expectedMD5SumBytes, err := base64.StdEncoding.DecodeString(strings.TrimSpace(expectedMD5Sum))
 		if err != nil {
 			// pro-actively close the connection
-			return "", iodine.New(drivers.InvalidDigest{Md5: expectedMD5Sum}, nil)
+			return drivers.ObjectMetadata{}, iodine.New(drivers.InvalidDigest{Md5: expectedMD5Sum}, nil)
 		}
 		expectedMD5Sum = hex.EncodeToString(expectedMD5SumBytes)
 	}
@@ -277,7 +468,7 @@ func (memory *memoryDriver) createObject(bucket, key, contentType, expectedMD5Su
 		readBytes = append(readBytes, byteBuffer[0:length]...)
 	}
 	if err != io.EOF {
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
 	md5SumBytes := hash.Sum(nil)
 	totalLength := len(readBytes)
@@ -289,14 +480,14 @@ func (memory *memoryDriver) createObject(bucket, key, contentType, expectedMD5Su
 	go debug.FreeOSMemory()
 	memory.lock.Unlock()
 	if !ok {
-		return "", iodine.New(drivers.InternalError{}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.InternalError{}, nil)
 	}
 
 	md5Sum := hex.EncodeToString(md5SumBytes)
 	// Verify if the written object is equal to what is expected, only if it is requested as such
 	if strings.TrimSpace(expectedMD5Sum) != "" {
 		if err := isMD5SumEqual(strings.TrimSpace(expectedMD5Sum), md5Sum); err != nil {
-			return "", iodine.New(drivers.BadDigest{Md5: expectedMD5Sum, Bucket: bucket, Key: key}, nil)
+			return drivers.ObjectMetadata{}, iodine.New(drivers.BadDigest{Md5: expectedMD5Sum, Bucket: bucket, Key: key}, nil)
 		}
 	}
 
@@ -308,13 +499,19 @@ func (memory *memoryDriver) createObject(bucket, key, contentType, expectedMD5Su
 		Created:     time.Now().UTC(),
 		Md5:         md5Sum,
 		Size:        int64(totalLength),
+		Metadata:    make(map[string]string),
+		Tags:        attributes.Tags,
+		ACL:         attributes.ACL,
+	}
+	for name, value := range attributes.Metadata {
+		newObject.Metadata[name] = value
 	}
 
 	memory.lock.Lock()
 	storedBucket.objectMetadata[objectKey] = newObject
 	memory.storedBuckets[bucket] = storedBucket
 	memory.lock.Unlock()
-	return newObject.Md5, nil
+	return newObject, nil
 }
 
 // CreateBucket - create bucket in memory
@@ -343,6 +540,7 @@ func (memory *memoryDriver) CreateBucket(bucketName, acl string) error {
 		acl = "private"
 	}
 	var newBucket = storedBucket{}
+	newBucket.bucketResources = make(map[string][]byte)
 	newBucket.objectMetadata = make(map[string]drivers.ObjectMetadata)
 	newBucket.multiPartSession = make(map[string]multiPartSession)
 	newBucket.partMetadata = make(map[string]drivers.PartMetadata)
@@ -509,6 +707,105 @@ func (memory *memoryDriver) GetObjectMetadata(bucket, key string) (drivers.Objec
 	return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
 }
 
+// DeleteObject - delete object from memory
+func (memory *memoryDriver) DeleteObject(bucket, key string) error {
+	memory.lock.Lock()
+	defer memory.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if !drivers.IsValidObjectName(key) {
+		return iodine.New(drivers.ObjectNameInvalid{Object: key}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket]; ok == false {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	storedBucket := memory.storedBuckets[bucket]
+	objectKey := bucket + "/" + key
+	if _, ok := storedBucket.objectMetadata[objectKey]; ok == false {
+		return iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+	}
+	memory.objects.Delete(objectKey)
+	delete(storedBucket.objectMetadata, objectKey)
+	return nil
+}
+
+// SetObjectTags - replace the tags of an object in memory
+func (memory *memoryDriver) SetObjectTags(bucket, key string, tags map[string]string) error {
+	memory.lock.Lock()
+	defer memory.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if !drivers.IsValidObjectName(key) {
+		return iodine.New(drivers.ObjectNameInvalid{Object: key}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket]; ok == false {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	storedBucket := memory.storedBuckets[bucket]
+	objectKey := bucket + "/" + key
+	object, ok := storedBucket.objectMetadata[objectKey]
+	if ok == false {
+		return iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+	}
+	object.Tags = tags
+	storedBucket.objectMetadata[objectKey] = object
+	return nil
+}
+
+// SetObjectACL - replace the ACL grants of an object in memory
+func (memory *memoryDriver) SetObjectACL(bucket, key string, grants []drivers.Grant) error {
+	memory.lock.Lock()
+	defer memory.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if !drivers.IsValidObjectName(key) {
+		return iodine.New(drivers.ObjectNameInvalid{Object: key}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket]; ok == false {
+		return iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	storedBucket := memory.storedBuckets[bucket]
+	objectKey := bucket + "/" + key
+	object, ok := storedBucket.objectMetadata[objectKey]
+	if ok == false {
+		return iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+	}
+	object.ACL = grants
+	storedBucket.objectMetadata[objectKey] = object
+	return nil
+}
+
+// DeleteObjects - delete several objects of a bucket from memory, errors are returned per key
+func (memory *memoryDriver) DeleteObjects(bucket string, keys []string) (map[string]error, error) {
+	memory.lock.Lock()
+	defer memory.lock.Unlock()
+	if !drivers.IsValidBucket(bucket) {
+		return nil, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+	}
+	if _, ok := memory.storedBuckets[bucket]; ok == false {
+		return nil, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+	}
+	storedBucket := memory.storedBuckets[bucket]
+	errs := make(map[string]error)
+	for _, key := range keys {
+		if !drivers.IsValidObjectName(key) {
+			errs[key] = iodine.New(drivers.ObjectNameInvalid{Object: key}, nil)
+			continue
+		}
+		objectKey := bucket + "/" + key
+		if _, ok := storedBucket.objectMetadata[objectKey]; ok == false {
+			errs[key] = iodine.New(drivers.ObjectNotFound{Bucket: bucket, Object: key}, nil)
+			continue
+		}
+		memory.objects.Delete(objectKey)
+		delete(storedBucket.objectMetadata, objectKey)
+	}
+	return errs, nil
+}
+
 func (memory *memoryDriver) expiredObject(a ...interface{}) {
 	cacheStats := memory.objects.Stats()
 	log.Printf("CurrentSize: %d, CurrentItems: %d, TotalExpirations: %d",
@@ -35,7 +35,7 @@ import (
 	"github.com/minio/minio/pkg/storage/drivers"
 )
 
-func (memory *memoryDriver) NewMultipartUpload(bucket, key, contentType string) (string, error) {
+func (memory *memoryDriver) NewMultipartUpload(bucket, key, contentType string, attributes drivers.ObjectAttributes) (string, error) {
 	memory.lock.RLock()
 	if !drivers.IsValidBucket(bucket) {
 		memory.lock.RUnlock()
@@ -63,9 +63,11 @@ func (memory *memoryDriver) NewMultipartUpload(bucket, key, contentType string)
 	uploadID := base64.URLEncoding.EncodeToString(uploadIDSum[:])[:47]
 
 	memory.storedBuckets[bucket].multiPartSession[key] = multiPartSession{
-		uploadID:   uploadID,
-		initiated:  time.Now(),
-		totalParts: 0,
+		uploadID:    uploadID,
+		initiated:   time.Now(),
+		totalParts:  0,
+		contentType: contentType,
+		attributes:  attributes,
 	}
 	memory.lock.Unlock()
 
@@ -208,29 +210,33 @@ func (memory *memoryDriver) cleanupMultipartSession(bucket, key, uploadID string
 }
 
 func (memory *memoryDriver) cleanupMultiparts(bucket, key, uploadID string) {
+	memory.lock.Lock()
+	defer memory.lock.Unlock()
 	for i := 1; i <= memory.storedBuckets[bucket].multiPartSession[key].totalParts; i++ {
 		objectKey := bucket + "/" + getMultipartKey(key, uploadID, i)
 		memory.multiPartObjects.Delete(objectKey)
+		delete(memory.storedBuckets[bucket].partMetadata, objectKey)
 	}
 }
 
-func (memory *memoryDriver) CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (string, error) {
+func (memory *memoryDriver) CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (drivers.ObjectMetadata, error) {
 	if !drivers.IsValidBucket(bucket) {
-		return "", iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNameInvalid{Bucket: bucket}, nil)
 	}
 	if !drivers.IsValidObjectName(key) {
-		return "", iodine.New(drivers.ObjectNameInvalid{Object: key}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.ObjectNameInvalid{Object: key}, nil)
 	}
 	// Verify upload id
 	memory.lock.RLock()
 	if _, ok := memory.storedBuckets[bucket]; ok == false {
 		memory.lock.RUnlock()
-		return "", iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.BucketNotFound{Bucket: bucket}, nil)
 	}
 	storedBucket := memory.storedBuckets[bucket]
-	if storedBucket.multiPartSession[key].uploadID != uploadID {
+	session := storedBucket.multiPartSession[key]
+	if session.uploadID != uploadID {
 		memory.lock.RUnlock()
-		return "", iodine.New(drivers.InvalidUploadID{UploadID: uploadID}, nil)
+		return drivers.ObjectMetadata{}, iodine.New(drivers.InvalidUploadID{UploadID: uploadID}, nil)
 	}
 	memory.lock.RUnlock()
 
@@ -242,21 +248,21 @@ func (memory *memoryDriver) CompleteMultipartUpload(bucket, key, uploadID string
 		object, ok := memory.multiPartObjects.Get(bucket + "/" + getMultipartKey(key, uploadID, i))
 		if ok == false {
 			memory.lock.Unlock()
-			return "", iodine.New(errors.New("missing part: "+strconv.Itoa(i)), nil)
+			return drivers.ObjectMetadata{}, iodine.New(errors.New("missing part: "+strconv.Itoa(i)), nil)
 		}
 		size += int64(len(object))
 		calcMD5Bytes := md5.Sum(object)
 		// complete multi part request header md5sum per part is hex encoded
 		recvMD5Bytes, err := hex.DecodeString(strings.Trim(recvMD5, "\""))
 		if err != nil {
-			return "", iodine.New(drivers.InvalidDigest{Md5: recvMD5}, nil)
+			return drivers.ObjectMetadata{}, iodine.New(drivers.InvalidDigest{Md5: recvMD5}, nil)
 		}
 		if !bytes.Equal(recvMD5Bytes, calcMD5Bytes[:]) {
-			return "", iodine.New(drivers.BadDigest{Md5: recvMD5, Bucket: bucket, Key: getMultipartKey(key, uploadID, i)}, nil)
+			return drivers.ObjectMetadata{}, iodine.New(drivers.BadDigest{Md5: recvMD5, Bucket: bucket, Key: getMultipartKey(key, uploadID, i)}, nil)
 		}
 		_, err = io.Copy(&fullObject, bytes.NewBuffer(object))
 		if err != nil {
-			return "", iodine.New(err, nil)
+			return drivers.ObjectMetadata{}, iodine.New(err, nil)
 		}
 		object = nil
 		go debug.FreeOSMemory()
@@ -266,16 +272,16 @@ func (memory *memoryDriver) CompleteMultipartUpload(bucket, key, uploadID string
 	md5sumSlice := md5.Sum(fullObject.Bytes())
 	// this is needed for final verification inside CreateObject, do not convert this to hex
 	md5sum := base64.StdEncoding.EncodeToString(md5sumSlice[:])
-	etag, err := memory.CreateObject(bucket, key, "", md5sum, size, &fullObject)
+	objectMetadata, err := memory.CreateObject(bucket, key, session.contentType, md5sum, size, &fullObject, session.attributes)
 	if err != nil {
 		// No need to call internal cleanup functions here, caller will call AbortMultipartUpload()
 		// which would in-turn cleanup properly in accordance with S3 Spec
-		return "", iodine.New(err, nil)
+		return drivers.ObjectMetadata{}, iodine.New(err, nil)
 	}
 	fullObject.Reset()
 	memory.cleanupMultiparts(bucket, key, uploadID)
 	memory.cleanupMultipartSession(bucket, key, uploadID)
-	return etag, nil
+	return objectMetadata, nil
 }
 
 // byKey is a sortable interface for UploadMetadata slice
@@ -53,11 +53,73 @@ func (m *Driver) SetBucketMetadata(bucket, acl string) error {
 	return r0
 }
 
+// DeleteBucket is a mock
+func (m *Driver) DeleteBucket(bucket string) error {
+	ret := m.Called(bucket)
+
+	r0 := ret.Error(0)
+
+	return r0
+}
+
 // SetGetObjectWriter is a mock
 func (m *Driver) SetGetObjectWriter(bucket, object string, data []byte) {
 	m.ObjectWriterData[bucket+":"+object] = data
 }
 
+// GetBucketResource is a mock, buckets carry no resources unless a call is expected
+func (m *Driver) GetBucketResource(bucket, resource string) ([]byte, error) {
+	if !m.isExpected("GetBucketResource") {
+		return nil, iodine.New(drivers.BucketResourceNotFound{Bucket: bucket, Resource: resource}, nil)
+	}
+	ret := m.Called(bucket, resource)
+
+	r0 := ret.Get(0).([]byte)
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
+
+// SetBucketResource is a mock
+func (m *Driver) SetBucketResource(bucket, resource string, data []byte) error {
+	ret := m.Called(bucket, resource, data)
+
+	r0 := ret.Error(0)
+
+	return r0
+}
+
+// DeleteBucketResource is a mock
+func (m *Driver) DeleteBucketResource(bucket, resource string) error {
+	ret := m.Called(bucket, resource)
+
+	r0 := ret.Error(0)
+
+	return r0
+}
+
+// GetBucketVersioning is a mock, buckets are not versioned unless a call is expected
+func (m *Driver) GetBucketVersioning(bucket string) (string, error) {
+	if !m.isExpected("GetBucketVersioning") {
+		return "", nil
+	}
+	ret := m.Called(bucket)
+
+	r0 := ret.Get(0).(string)
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
+
+// SetBucketVersioning is a mock
+func (m *Driver) SetBucketVersioning(bucket, status string) error {
+	ret := m.Called(bucket, status)
+
+	r0 := ret.Error(0)
+
+	return r0
+}
+
 // GetObject is a mock
 func (m *Driver) GetObject(w io.Writer, bucket, object string) (int64, error) {
 	ret := m.Called(w, bucket, object)
@@ -118,18 +180,125 @@ func (m *Driver) ListObjects(bucket string, resources drivers.BucketResourcesMet
 }
 
 // CreateObject is a mock
-func (m *Driver) CreateObject(bucket, key, contentType, md5sum string, size int64, data io.Reader) (string, error) {
-	ret := m.Called(bucket, key, contentType, md5sum, size, data)
+func (m *Driver) CreateObject(bucket, key, contentType, md5sum string, size int64, data io.Reader, attributes drivers.ObjectAttributes) (drivers.ObjectMetadata, error) {
+	ret := m.Called(bucket, key, contentType, md5sum, size, data, attributes)
 
-	r0 := ret.Get(0).(string)
+	r0 := ret.Get(0).(drivers.ObjectMetadata)
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
+
+// CopyObject is a mock
+func (m *Driver) CopyObject(sourceBucket, sourceKey, bucket, key, contentType string, metadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	ret := m.Called(sourceBucket, sourceKey, bucket, key, contentType, metadata, grants)
+
+	r0 := ret.Get(0).(drivers.ObjectMetadata)
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
+
+// DeleteObject is a mock
+func (m *Driver) DeleteObject(bucket, key string) error {
+	ret := m.Called(bucket, key)
+
+	r0 := ret.Error(0)
+
+	return r0
+}
+
+// DeleteObjects is a mock
+func (m *Driver) DeleteObjects(bucket string, keys []string) (map[string]error, error) {
+	ret := m.Called(bucket, keys)
+
+	r0 := ret.Get(0).(map[string]error)
 	r1 := ret.Error(1)
 
 	return r0, r1
 }
 
+// SetObjectTags is a mock
+func (m *Driver) SetObjectTags(bucket, key string, tags map[string]string) error {
+	ret := m.Called(bucket, key, tags)
+
+	r0 := ret.Error(0)
+
+	return r0
+}
+
+// SetObjectACL is a mock
+func (m *Driver) SetObjectACL(bucket, key string, grants []drivers.Grant) error {
+	ret := m.Called(bucket, key, grants)
+
+	r0 := ret.Error(0)
+
+	return r0
+}
+
+// GetObjectVersion is a mock
+func (m *Driver) GetObjectVersion(w io.Writer, bucket, key, versionID string) (int64, error) {
+	ret := m.Called(w, bucket, key, versionID)
+
+	r0 := ret.Get(0).(int64)
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
+
+// GetPartialObjectVersion is a mock
+func (m *Driver) GetPartialObjectVersion(w io.Writer, bucket, key, versionID string, start, length int64) (int64, error) {
+	ret := m.Called(w, bucket, key, versionID, start, length)
+
+	r0 := ret.Get(0).(int64)
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
+
+// GetObjectVersionMetadata is a mock
+func (m *Driver) GetObjectVersionMetadata(bucket, key, versionID string) (drivers.ObjectMetadata, error) {
+	ret := m.Called(bucket, key, versionID)
+
+	r0 := ret.Get(0).(drivers.ObjectMetadata)
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
+
+// CopyObjectVersion is a mock
+func (m *Driver) CopyObjectVersion(sourceBucket, sourceKey, sourceVersionID, bucket, key, contentType string, metadata map[string]string, grants []drivers.Grant) (drivers.ObjectMetadata, error) {
+	ret := m.Called(sourceBucket, sourceKey, sourceVersionID, bucket, key, contentType, metadata, grants)
+
+	r0 := ret.Get(0).(drivers.ObjectMetadata)
+	r1 := ret.Error(1)
+
+	return r0, r1
+}
+
+// DeleteObjectVersion is a mock
+func (m *Driver) DeleteObjectVersion(bucket, key, versionID string) error {
+	ret := m.Called(bucket, key, versionID)
+
+	r0 := ret.Error(0)
+
+	return r0
+}
+
+// ListObjectVersions is a mock
+func (m *Driver) ListObjectVersions(bucket string, resources drivers.BucketResourcesMetadata) ([]drivers.ObjectMetadata, drivers.BucketResourcesMetadata, error) {
+	ret := m.Called(bucket, resources)
+
+	r0 := ret.Get(0).([]drivers.ObjectMetadata)
+	r1 := ret.Get(1).(drivers.BucketResourcesMetadata)
+	r2 := ret.Error(2)
+
+	return r0, r1, r2
+}
+
 // NewMultipartUpload is a mock
-func (m *Driver) NewMultipartUpload(bucket, key, contentType string) (string, error) {
-	ret := m.Called(bucket, key, contentType)
+func (m *Driver) NewMultipartUpload(bucket, key, contentType string, attributes drivers.ObjectAttributes) (string, error) {
+	ret := m.Called(bucket, key, contentType, attributes)
 
 	r0 := ret.Get(0).(string)
 	r1 := ret.Error(1)
@@ -148,10 +317,10 @@ func (m *Driver) CreateObjectPart(bucket, key, uploadID string, partID int, cont
 }
 
 // CompleteMultipartUpload is a mock
-func (m *Driver) CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (string, error) {
+func (m *Driver) CompleteMultipartUpload(bucket, key, uploadID string, parts map[int]string) (drivers.ObjectMetadata, error) {
 	ret := m.Called(bucket, key, uploadID, parts)
 
-	r0 := ret.Get(0).(string)
+	r0 := ret.Get(0).(drivers.ObjectMetadata)
 	r1 := ret.Error(1)
 
 	return r0, r1
@@ -185,3 +354,13 @@ func (m *Driver) AbortMultipartUpload(bucket, key, uploadID string) error {
 
 	return r0
 }
+
+// isExpected - whether any call of the method is expected at all
+func (m *Driver) isExpected(method string) bool {
+	for _, call := range m.ExpectedCalls {
+		if call.Method == method {
+			return true
+		}
+	}
+	return false
+}
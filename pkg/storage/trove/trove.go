@@ -151,11 +151,15 @@ func (r *Cache) Expire() {
 	}
 }
 
-// Delete deletes a given key if exists
+// Delete deletes a given key if exists, OnExpired is not invoked since this is an explicit removal
 func (r *Cache) Delete(key string) {
 	r.Lock()
 	defer r.Unlock()
-	r.doDelete(key)
+	if _, ok := r.items[key]; ok {
+		r.currentSize -= uint64(len(r.items[key]))
+		delete(r.items, key)
+		delete(r.updatedAt, key)
+	}
 }
 
 func (r *Cache) doDelete(key string) {